@@ -11,6 +11,15 @@ var Server = fetcher.Preset{
 var apiLogin = Server.EndPoint("GET", "/sns/jscode2session")
 var APIToken = Server.EndPoint("GET", "/cgi-bin/token")
 
+const APIErrAccessTokenNotLast = 40001
+const APIErrAccessTokenWrong = 40014
+const APIErrAccessTokenOutOfDate = 42001
+
+//IsAccessTokenErrCode check if given errcode means access token is rejected.
+func IsAccessTokenErrCode(code int) bool {
+	return code == APIErrAccessTokenNotLast || code == APIErrAccessTokenWrong || code == APIErrAccessTokenOutOfDate
+}
+
 type ResultAPIError struct {
 	Errcode int    `json:"errcode"`
 	Errmsg  string `json:"errmsg"`
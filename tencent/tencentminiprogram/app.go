@@ -3,16 +3,21 @@ package tencentminiprogram
 import (
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechattoken"
 )
 
 type App struct {
 	AppID              string
 	AppSecret          string
 	Client             fetcher.Client
-	accessToken        string
+	store              wechattoken.Store
+	refreshAhead       time.Duration
+	refreshing         bool
 	lock               sync.Mutex
+	grantLock          sync.Mutex
 	accessTokenGetter  func() (string, error)
 	accessTokenCreator func() (string, error)
 }
@@ -38,19 +43,108 @@ func (a *App) Login(code string) (*ResultUserInfo, error) {
 	return result, nil
 }
 
+//SetAccessTokenGetter set func which loads access token.
+//Deprecated: use SetAccessTokenStore instead.
 func (a *App) SetAccessTokenGetter(f func() (string, error)) {
 	a.accessTokenGetter = f
 }
 func (a *App) SetAccessTokenCreator(f func() (string, error)) {
 	a.accessTokenCreator = f
 }
-func (a *App) AccessToken() (string, error) {
+
+//SetAccessTokenRefreshAhead set duration before expiry when access token should be refreshed.
+//wechattoken.DefaultRefreshAhead will be used if d is not positive.
+func (a *App) SetAccessTokenRefreshAhead(d time.Duration) {
 	a.lock.Lock()
 	defer a.lock.Unlock()
+	a.refreshAhead = d
+}
+
+//getRefreshAhead return refresh ahead duration.
+//Lock should be held by caller.
+func (a *App) getRefreshAhead() time.Duration {
+	if a.refreshAhead > 0 {
+		return a.refreshAhead
+	}
+	return wechattoken.DefaultRefreshAhead
+}
+func (a *App) loadAccessToken() (*wechattoken.Token, error) {
 	if a.accessTokenGetter != nil {
-		return a.accessTokenGetter()
+		v, err := a.accessTokenGetter()
+		if err != nil {
+			return nil, err
+		}
+		return wechattoken.NewToken(v, 0), nil
+	}
+	t, err := a.getStore().Get(a.AccessTokenKey())
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return &wechattoken.Token{}, nil
+	}
+	return t, nil
+}
+
+//SetAccessTokenStore set store which access token is saved in.
+//Access token will be saved in memory if store is not set.
+func (a *App) SetAccessTokenStore(s wechattoken.Store) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.store = s
+}
+
+//AccessTokenKey return key by which access token is saved in store.
+func (a *App) AccessTokenKey() string {
+	return "tencentminiprogram/" + a.AppID
+}
+
+func (a *App) getStore() wechattoken.Store {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.store == nil {
+		a.store = wechattoken.NewMemoryStore()
+	}
+	return a.store
+}
+
+//saveAccessToken save granted token to store if current token in store is still old.
+//Token in store will be returned if it has been replaced by others.
+func (a *App) saveAccessToken(old string, token *wechattoken.Token) (*wechattoken.Token, error) {
+	store := a.getStore()
+	ok, err := store.CompareAndSet(a.AccessTokenKey(), old, token)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return token, nil
+	}
+	current, err := store.Get(a.AccessTokenKey())
+	if err != nil {
+		return nil, err
+	}
+	if current.IsEmpty() {
+		return token, nil
 	}
-	return a.accessToken, nil
+	return current, nil
+}
+
+func (a *App) AccessToken() (string, error) {
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
+
+//AccessTokenExpiresAt return expiry time of current access token.
+//Zero time will be returned if expiry is unknown.
+func (a *App) AccessTokenExpiresAt() (time.Time, error) {
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.ExpiresAt, nil
 }
 func (a *App) ClientCredentialBuilder() fetcher.Command {
 	return fetcher.ParamsBuilderFunc(func(params url.Values) error {
@@ -62,36 +156,137 @@ func (a *App) ClientCredentialBuilder() fetcher.Command {
 	})
 }
 
-func (a *App) GetAccessToken() (string, error) {
+func (a *App) requestAccessToken() (*wechattoken.Token, error) {
 	result := &resultAccessToken{}
 	resp, err := APIToken.With(
 		&a.Client,
 		a.ClientCredentialBuilder()).
 		FetchAndParse(fetcher.AsJSON(result))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if result.Errcode != 0 || result.Errmsg != "" || result.AccessToken == "" {
-		return "", resp.NewAPICodeErr(result.Errcode)
+		return nil, resp.NewAPICodeErr(result.Errcode)
+	}
+	return wechattoken.NewToken(result.AccessToken, result.ExpiresIn), nil
+}
+
+func (a *App) GetAccessToken() (string, error) {
+	t, err := a.requestAccessToken()
+	if err != nil {
+		return "", err
 	}
-	return result.AccessToken, nil
+	return t.Value, nil
 }
 
+//GrantAccessToken grant new access token and save it to store.
 func (a *App) GrantAccessToken() (string, error) {
-	var token string
-	var err error
-	a.lock.Lock()
-	defer a.lock.Unlock()
+	a.grantLock.Lock()
+	defer a.grantLock.Unlock()
+	t, err := a.grantAccessToken()
+	if err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
 
+//grantAccessToken grant new access token.
+//Grant lock should be held by caller.
+func (a *App) grantAccessToken() (*wechattoken.Token, error) {
+	var token *wechattoken.Token
+	old, err := a.loadAccessToken()
+	if err != nil {
+		return nil, err
+	}
 	if a.accessTokenCreator == nil {
-		token, err = a.GetAccessToken()
+		token, err = a.requestAccessToken()
 	} else {
-		token, err = a.accessTokenCreator()
+		var v string
+		v, err = a.accessTokenCreator()
+		token = wechattoken.NewToken(v, 0)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return a.saveAccessToken(old.Value, token)
+}
+
+//RefreshAccessToken refresh access token which is rejected with given old value.
+//Current token will be returned without granting if old token has already been replaced.
+func (a *App) RefreshAccessToken(old string) (string, error) {
+	t, err := a.refreshAccessToken(old)
+	if err != nil {
+		return "", err
 	}
+	return t.Value, nil
+}
+
+func (a *App) refreshAccessToken(old string) (*wechattoken.Token, error) {
+	a.grantLock.Lock()
+	defer a.grantLock.Unlock()
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	if t.Value != old && !t.IsExpired(time.Now()) {
+		return t, nil
+	}
+	return a.grantAccessToken()
+}
 
+//RefreshAccessTokenIfNeeded grant new access token if current token is empty or about to expire.
+func (a *App) RefreshAccessTokenIfNeeded() error {
+	a.grantLock.Lock()
+	defer a.grantLock.Unlock()
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return err
+	}
+	a.lock.Lock()
+	ahead := a.getRefreshAhead()
+	a.lock.Unlock()
+	if !t.NeedRefresh(time.Now(), ahead) {
+		return nil
+	}
+	_, err = a.grantAccessToken()
+	return err
+}
+
+//NewBackgroundRefresher create background refresher which checks access token in given interval
+//and refreshes it before expired.
+func (a *App) NewBackgroundRefresher(interval time.Duration) *wechattoken.BackgroundRefresher {
+	return wechattoken.NewBackgroundRefresher(interval, a.RefreshAccessTokenIfNeeded)
+}
+
+func (a *App) refreshInBackground() {
+	a.RefreshAccessTokenIfNeeded()
+	a.lock.Lock()
+	a.refreshing = false
+	a.lock.Unlock()
+}
+
+//ValidAccessToken return current access token.
+//New token will be granted if current token is empty or expired.
+//Refresh will be started in background if current token is about to expire.
+func (a *App) ValidAccessToken() (string, error) {
+	t, err := a.loadAccessToken()
 	if err != nil {
 		return "", err
 	}
-	a.accessToken = token
-	return token, nil
+	now := time.Now()
+	if t.IsExpired(now) {
+		t, err = a.refreshAccessToken(t.Value)
+		if err != nil {
+			return "", err
+		}
+		return t.Value, nil
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if t.NeedRefresh(now, a.getRefreshAhead()) && !a.refreshing {
+		a.refreshing = true
+		go a.refreshInBackground()
+	}
+	return t.Value, nil
 }
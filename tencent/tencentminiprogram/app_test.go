@@ -0,0 +1,67 @@
+package tencentminiprogram
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechattoken"
+)
+
+func TestValidAccessTokenNotBlockedByGrant(t *testing.T) {
+	app := &App{AppID: "appid"}
+	store := wechattoken.NewMemoryStore()
+	_, err := store.CompareAndSet(app.AccessTokenKey(), "", wechattoken.NewToken("token", 7200))
+	if err != nil {
+		panic(err)
+	}
+	app.SetAccessTokenStore(store)
+	var grants int32
+	granting := make(chan struct{})
+	release := make(chan struct{})
+	app.SetAccessTokenCreator(func() (string, error) {
+		if atomic.AddInt32(&grants, 1) == 1 {
+			close(granting)
+		}
+		<-release
+		return "newtoken", nil
+	})
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := app.RefreshAccessToken("token")
+			if err != nil || v != "newtoken" {
+				panic(v)
+			}
+		}()
+	}
+	<-granting
+	done := make(chan string)
+	go func() {
+		v, err := app.ValidAccessToken()
+		if err != nil {
+			panic(err)
+		}
+		done <- v
+	}()
+	select {
+	case v := <-done:
+		if v != "token" {
+			t.Fatal(v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("valid access token blocked by grant")
+	}
+	close(release)
+	wg.Wait()
+	if grants != 1 {
+		t.Fatal(grants)
+	}
+	v, err := app.ValidAccessToken()
+	if err != nil || v != "newtoken" {
+		t.Fatal(v, err)
+	}
+}
@@ -44,11 +44,7 @@ type Message struct {
 func NewMessage() *Message {
 	return &Message{}
 }
-func Send(app *tencentminiprogram.App, msg *Message) error {
-	token, err := app.GetAccessToken()
-	if err != nil {
-		return err
-	}
+func send(app *tencentminiprogram.App, token string, msg *Message) (*ResultAPIError, *fetcher.Response, error) {
 	params := url.Values{}
 	params.Set("access_token", token)
 	result := &ResultAPIError{}
@@ -58,6 +54,33 @@ func Send(app *tencentminiprogram.App, msg *Message) error {
 		fetcher.JSONBody(msg),
 	)
 	resp, err := preset.FetchAndParse(fetcher.Should200(fetcher.AsJSON(result)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, resp, nil
+}
+
+//Send send uniform message.
+//Message will be sent again with refreshed access token if token is rejected.
+func Send(app *tencentminiprogram.App, msg *Message) error {
+	token, err := app.ValidAccessToken()
+	if err != nil {
+		return err
+	}
+	result, resp, err := send(app, token, msg)
+	if err != nil {
+		return err
+	}
+	if tencentminiprogram.IsAccessTokenErrCode(result.Errcode) {
+		token, err = app.RefreshAccessToken(token)
+		if err != nil {
+			return err
+		}
+		result, resp, err = send(app, token, msg)
+		if err != nil {
+			return err
+		}
+	}
 	if result.Errcode != 0 {
 		return resp.NewAPICodeErr(result.Errcode)
 	}
@@ -2,6 +2,7 @@ package wechatmp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/herb-go/fetcher"
@@ -10,23 +11,141 @@ import (
 const ScopeSnsapiBase = "snsapi_base"
 const ScopeSnsapiUserinfo = "snsapi_userinfo"
 
+//AccessTokenModeClassic access token mode which grants token from /cgi-bin/token.
+//Tokens granted before will be invalidated.
+const AccessTokenModeClassic = "classic"
+
+//AccessTokenModeStable access token mode which grants token from /cgi-bin/stable_token.
+//Tokens are shared by all callers and only force refreshed when rejected.
+const AccessTokenModeStable = "stable"
+
 var Server = fetcher.MustPreset(&fetcher.ServerInfo{
 	URL: "https://api.weixin.qq.com",
 })
 
 var APIGetUserInfo = Server.EndPoint("GET", "/sns/userinfo")
 var APIToken = Server.EndPoint("GET", "/cgi-bin/token")
+var APIStableToken = Server.EndPoint("POST", "/cgi-bin/stable_token")
 var APIOauth2AccessToken = Server.EndPoint("GET", "/sns/oauth2/access_token")
+var APIOauth2RefreshToken = Server.EndPoint("GET", "/sns/oauth2/refresh_token")
+var APISnsAuth = Server.EndPoint("GET", "/sns/auth")
+
+var APITicketGet = Server.EndPoint("GET", "/cgi-bin/ticket/getticket")
 
 var APIMenuCreate = Server.EndPoint("POST", "/cgi-bin/menu/create")
 
 var APIMenuGet = Server.EndPoint("GET", "/cgi-bin/menu/get")
+var APIMenuDelete = Server.EndPoint("GET", "/cgi-bin/menu/delete")
+var APIMenuAddConditional = Server.EndPoint("POST", "/cgi-bin/menu/addconditional")
+var APIMenuDelConditional = Server.EndPoint("POST", "/cgi-bin/menu/delconditional")
+var APIMenuTryMatch = Server.EndPoint("POST", "/cgi-bin/menu/trymatch")
 
 var APIQRCodeCreate = Server.EndPoint("POST", "/cgi-bin/qrcode/create")
 
+var MPServer = fetcher.MustPreset(&fetcher.ServerInfo{
+	URL: "https://mp.weixin.qq.com",
+})
+
+var APIShowQRCode = MPServer.EndPoint("GET", "/cgi-bin/showqrcode")
+
 var APIGetAllPrivateTemplate = Server.EndPoint("GET", "/cgi-bin/template/get_all_private_template")
 
 var APIMessageTemplateSend = Server.EndPoint("POST", "/cgi-bin/message/template/send")
+var APITemplateSetIndustry = Server.EndPoint("POST", "/cgi-bin/template/api_set_industry")
+var APITemplateGetIndustry = Server.EndPoint("GET", "/cgi-bin/template/get_industry")
+var APITemplateAdd = Server.EndPoint("POST", "/cgi-bin/template/api_add_template")
+var APITemplateDelPrivate = Server.EndPoint("POST", "/cgi-bin/template/del_private_template")
+
+var APIMessageSubscribeBizSend = Server.EndPoint("POST", "/cgi-bin/message/subscribe/bizsend")
+var APIMessageTemplateSubscribe = Server.EndPoint("POST", "/cgi-bin/message/template/subscribe")
+var APINewTmplGetTemplate = Server.EndPoint("GET", "/wxaapi/newtmpl/gettemplate")
+var APINewTmplGetCategory = Server.EndPoint("GET", "/wxaapi/newtmpl/getcategory")
+
+var APIUserGet = Server.EndPoint("GET", "/cgi-bin/user/get")
+var APIUserInfo = Server.EndPoint("GET", "/cgi-bin/user/info")
+var APIUserInfoBatchGet = Server.EndPoint("POST", "/cgi-bin/user/info/batchget")
+var APIUserInfoUpdateRemark = Server.EndPoint("POST", "/cgi-bin/user/info/updateremark")
+
+var APIMediaUpload = Server.EndPoint("POST", "/cgi-bin/media/upload")
+var APIMediaGet = Server.EndPoint("GET", "/cgi-bin/media/get")
+var APIMediaUploadImg = Server.EndPoint("POST", "/cgi-bin/media/uploadimg")
+var APIMaterialAdd = Server.EndPoint("POST", "/cgi-bin/material/add_material")
+var APIMaterialGet = Server.EndPoint("POST", "/cgi-bin/material/get_material")
+var APIMaterialDel = Server.EndPoint("POST", "/cgi-bin/material/del_material")
+var APIMaterialGetCount = Server.EndPoint("GET", "/cgi-bin/material/get_materialcount")
+var APIMaterialBatchGet = Server.EndPoint("POST", "/cgi-bin/material/batchget_material")
+var APIDraftAdd = Server.EndPoint("POST", "/cgi-bin/draft/add")
+var APIDraftUpdate = Server.EndPoint("POST", "/cgi-bin/draft/update")
+var APIDraftGet = Server.EndPoint("POST", "/cgi-bin/draft/get")
+var APIDraftDelete = Server.EndPoint("POST", "/cgi-bin/draft/delete")
+var APIDraftBatchGet = Server.EndPoint("POST", "/cgi-bin/draft/batchget")
+var APIDraftCount = Server.EndPoint("GET", "/cgi-bin/draft/count")
+var APIFreePublishSubmit = Server.EndPoint("POST", "/cgi-bin/freepublish/submit")
+var APIFreePublishGet = Server.EndPoint("POST", "/cgi-bin/freepublish/get")
+var APIFreePublishDelete = Server.EndPoint("POST", "/cgi-bin/freepublish/delete")
+var APIFreePublishGetArticle = Server.EndPoint("POST", "/cgi-bin/freepublish/getarticle")
+var APIFreePublishBatchGet = Server.EndPoint("POST", "/cgi-bin/freepublish/batchget")
+
+var APICommentOpen = Server.EndPoint("POST", "/cgi-bin/comment/open")
+var APICommentClose = Server.EndPoint("POST", "/cgi-bin/comment/close")
+var APICommentList = Server.EndPoint("POST", "/cgi-bin/comment/list")
+var APICommentMarkElect = Server.EndPoint("POST", "/cgi-bin/comment/markelect")
+var APICommentUnmarkElect = Server.EndPoint("POST", "/cgi-bin/comment/unmarkelect")
+var APICommentDelete = Server.EndPoint("POST", "/cgi-bin/comment/delete")
+var APICommentReplyAdd = Server.EndPoint("POST", "/cgi-bin/comment/reply/add")
+var APICommentReplyDelete = Server.EndPoint("POST", "/cgi-bin/comment/reply/delete")
+
+var APIMessageCustomSend = Server.EndPoint("POST", "/cgi-bin/message/custom/send")
+var APIMessageCustomTyping = Server.EndPoint("POST", "/cgi-bin/message/custom/typing")
+var APIKFAccountAdd = Server.EndPoint("POST", "/customservice/kfaccount/add")
+var APIKFAccountUpdate = Server.EndPoint("POST", "/customservice/kfaccount/update")
+var APIKFAccountDel = Server.EndPoint("GET", "/customservice/kfaccount/del")
+var APIKFList = Server.EndPoint("GET", "/cgi-bin/customservice/getkflist")
+var APIKFOnlineList = Server.EndPoint("GET", "/cgi-bin/customservice/getonlinekflist")
+var APIKFSessionCreate = Server.EndPoint("POST", "/customservice/kfsession/create")
+var APIKFSessionClose = Server.EndPoint("POST", "/customservice/kfsession/close")
+var APIKFSessionGet = Server.EndPoint("GET", "/customservice/kfsession/getsession")
+var APIKFSessionList = Server.EndPoint("GET", "/customservice/kfsession/getsessionlist")
+var APIKFSessionWaitCase = Server.EndPoint("GET", "/customservice/kfsession/getwaitcase")
+
+var APIMessageMassSendAll = Server.EndPoint("POST", "/cgi-bin/message/mass/sendall")
+var APIMessageMassSend = Server.EndPoint("POST", "/cgi-bin/message/mass/send")
+var APIMessageMassPreview = Server.EndPoint("POST", "/cgi-bin/message/mass/preview")
+var APIMessageMassDelete = Server.EndPoint("POST", "/cgi-bin/message/mass/delete")
+var APIMessageMassGet = Server.EndPoint("POST", "/cgi-bin/message/mass/get")
+
+var APIDataCubeGetUserSummary = Server.EndPoint("POST", "/datacube/getusersummary")
+var APIDataCubeGetUserCumulate = Server.EndPoint("POST", "/datacube/getusercumulate")
+var APIDataCubeGetArticleSummary = Server.EndPoint("POST", "/datacube/getarticlesummary")
+var APIDataCubeGetArticleTotal = Server.EndPoint("POST", "/datacube/getarticletotal")
+var APIDataCubeGetUserRead = Server.EndPoint("POST", "/datacube/getuserread")
+var APIDataCubeGetUserReadHour = Server.EndPoint("POST", "/datacube/getuserreadhour")
+var APIDataCubeGetUserShare = Server.EndPoint("POST", "/datacube/getusershare")
+var APIDataCubeGetUserShareHour = Server.EndPoint("POST", "/datacube/getusersharehour")
+var APIDataCubeGetUpstreamMsg = Server.EndPoint("POST", "/datacube/getupstreammsg")
+var APIDataCubeGetUpstreamMsgHour = Server.EndPoint("POST", "/datacube/getupstreammsghour")
+var APIDataCubeGetUpstreamMsgWeek = Server.EndPoint("POST", "/datacube/getupstreammsgweek")
+var APIDataCubeGetUpstreamMsgMonth = Server.EndPoint("POST", "/datacube/getupstreammsgmonth")
+var APIDataCubeGetUpstreamMsgDist = Server.EndPoint("POST", "/datacube/getupstreammsgdist")
+var APIDataCubeGetUpstreamMsgDistWeek = Server.EndPoint("POST", "/datacube/getupstreammsgdistweek")
+var APIDataCubeGetUpstreamMsgDistMonth = Server.EndPoint("POST", "/datacube/getupstreammsgdistmonth")
+var APIDataCubeGetInterfaceSummary = Server.EndPoint("POST", "/datacube/getinterfacesummary")
+var APIDataCubeGetInterfaceSummaryHour = Server.EndPoint("POST", "/datacube/getinterfacesummaryhour")
+
+var APITagsCreate = Server.EndPoint("POST", "/cgi-bin/tags/create")
+var APITagsGet = Server.EndPoint("GET", "/cgi-bin/tags/get")
+var APITagsUpdate = Server.EndPoint("POST", "/cgi-bin/tags/update")
+var APITagsDelete = Server.EndPoint("POST", "/cgi-bin/tags/delete")
+var APIUserTagGet = Server.EndPoint("POST", "/cgi-bin/user/tag/get")
+var APITagsMembersBatchTagging = Server.EndPoint("POST", "/cgi-bin/tags/members/batchtagging")
+var APITagsMembersBatchUntagging = Server.EndPoint("POST", "/cgi-bin/tags/members/batchuntagging")
+var APITagsGetIDList = Server.EndPoint("POST", "/cgi-bin/tags/getidlist")
+var APITagsMembersGetBlackList = Server.EndPoint("POST", "/cgi-bin/tags/members/getblacklist")
+var APITagsMembersBatchBlackList = Server.EndPoint("POST", "/cgi-bin/tags/members/batchblacklist")
+var APITagsMembersBatchUnblackList = Server.EndPoint("POST", "/cgi-bin/tags/members/batchunblacklist")
+
+//ErrUploadBodyNotSeekable error raised when upload should be resent but body is not seekable.
+var ErrUploadBodyNotSeekable = errors.New("wechatmp: upload body is not seekable")
 
 const APIErrAccessTokenNotLast = 40001
 const APIErrAccessTokenWrong = 40014
@@ -34,6 +153,14 @@ const APIErrAccessTokenOutOfDate = 42001
 const APIErrSuccess = 0
 const APIErrUserUnaccessible = 50002
 const APIErrOauthCodeWrong = 40029
+const APIErrOpenIDWrong = 40003
+const APIErrRefreshTokenOutOfDate = 42002
+
+//IsErrorRefreshTokenOutOfDate check if error is raised by out of date user refresh token.
+//User should be authorized again in this case.
+func IsErrorRefreshTokenOutOfDate(err error) bool {
+	return fetcher.CompareAPIErrCode(err, APIErrRefreshTokenOutOfDate)
+}
 
 const APIResultGenderMale = 1
 const APIResultGenderFemale = 2
@@ -58,6 +185,20 @@ type resultAccessToken struct {
 	ExpiresIn   int    `json:"expires_in"`
 }
 
+type paramsStableToken struct {
+	GrantType    string `json:"grant_type"`
+	AppID        string `json:"appid"`
+	Secret       string `json:"secret"`
+	ForceRefresh bool   `json:"force_refresh"`
+}
+
+type resultTicket struct {
+	Errcode   int    `json:"errcode"`
+	Errmsg    string `json:"errmsg"`
+	Ticket    string `json:"ticket"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
 type resultOauthToken struct {
 	Errcode      int    `json:"errcode"`
 	Errmsg       string `json:"errmsg"`
@@ -65,7 +206,7 @@ type resultOauthToken struct {
 	ExpiresIn    int    `json:"expires_in"`
 	RefreshToken string `json:"refresh_token"`
 	OpenID       string `json:"openid"`
-	scope        string `json:"scope"`
+	Scope        string `json:"scope"`
 	UnionID      string `json:"unionid"`
 }
 type resultUserDetail struct {
@@ -121,6 +262,16 @@ func NewTemplateMessage() *TemplateMessage {
 	return &TemplateMessage{}
 }
 
+//SetData marshal given data to json and set as message data.
+func (m *TemplateMessage) SetData(data interface{}) error {
+	bs, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	m.Data = bs
+	return nil
+}
+
 type TemplateMessageSendResult struct {
 	Errcode int    `json:"errcode"`
 	Errmsg  string `json:"errmsg"`
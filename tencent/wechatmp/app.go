@@ -1,64 +1,168 @@
 package wechatmp
 
 import (
+	"bytes"
+	"io"
+	"mime/multipart"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechattoken"
 	"github.com/herb-go/remoteprocedure/fetcherapi/sharedrefresherapi"
 )
 
 type App struct {
-	AppID                string
-	AppSecret            string
+	AppID     string
+	AppSecret string
+	//AccessTokenMode mode used to grant access token.
+	//Value should be AccessTokenModeClassic or AccessTokenModeStable.
+	//AccessTokenModeClassic will be used if empty.
+	AccessTokenMode      string
 	RemoteRefresher      *fetcher.Server
 	Client               fetcher.Client
-	accessToken          string
+	store                wechattoken.Store
+	refreshAhead         time.Duration
+	refreshing           bool
 	lock                 sync.Mutex
+	grantLock            sync.Mutex
+	ticketLock           sync.Mutex
 	accessTokenGetter    func() (string, error)
 	accessTokenRefresher func(string) (string, error)
+	accessTokenProvider  wechattoken.Provider
 }
 
 //RefreshShared refresh shared data.
 //New data what different from old should be returned
 func (a *App) RefreshShared(old []byte) ([]byte, error) {
-	var t string
-	var err error
-	a.lock.Lock()
-	defer a.lock.Unlock()
-	oldtoken := string(old)
-	t, err = a.loadAccessToken()
+	t, err := a.refreshAccessToken(string(old))
 	if err != nil {
 		return nil, err
 	}
-	if t != "" && oldtoken != t {
-		return []byte(t), nil
-	}
-	t, err = a.GrantAccessToken()
-	if err != nil {
-		return nil, err
-	}
-	return []byte(t), nil
+	return []byte(t.Value), nil
+}
+
+//RefreshSharedToken return current token if it is different from old,
+//or refresh and return new token otherwise.
+func (a *App) RefreshSharedToken(old string) (*wechattoken.Token, error) {
+	return a.refreshAccessToken(old)
 }
 
+//SetAccessTokenProvider set provider which new access token is granted from.
+func (a *App) SetAccessTokenProvider(p wechattoken.Provider) {
+	a.accessTokenProvider = p
+}
+
+//SetAccessTokenGetter set func which loads access token.
+//Deprecated: use SetAccessTokenStore instead.
 func (a *App) SetAccessTokenGetter(f func() (string, error)) {
 	a.accessTokenGetter = f
 }
 func (a *App) SetAccessTokenRefresher(f func(string) (string, error)) {
 	a.accessTokenRefresher = f
 }
-func (a *App) AccessToken() (string, error) {
+
+//SetAccessTokenRefreshAhead set duration before expiry when access token should be refreshed.
+//wechattoken.DefaultRefreshAhead will be used if d is not positive.
+func (a *App) SetAccessTokenRefreshAhead(d time.Duration) {
 	a.lock.Lock()
 	defer a.lock.Unlock()
-	return a.loadAccessToken()
+	a.refreshAhead = d
+}
 
+//getRefreshAhead return refresh ahead duration.
+//Lock should be held by caller.
+func (a *App) getRefreshAhead() time.Duration {
+	if a.refreshAhead > 0 {
+		return a.refreshAhead
+	}
+	return wechattoken.DefaultRefreshAhead
 }
-func (a *App) loadAccessToken() (string, error) {
+func (a *App) AccessToken() (string, error) {
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
+
+//AccessTokenExpiresAt return expiry time of current access token.
+//Zero time will be returned if expiry is unknown.
+func (a *App) AccessTokenExpiresAt() (time.Time, error) {
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.ExpiresAt, nil
+}
+func (a *App) loadAccessToken() (*wechattoken.Token, error) {
 	if a.accessTokenGetter != nil {
-		return a.accessTokenGetter()
+		v, err := a.accessTokenGetter()
+		if err != nil {
+			return nil, err
+		}
+		return wechattoken.NewToken(v, 0), nil
+	}
+	t, err := a.getStore().Get(a.AccessTokenKey())
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return &wechattoken.Token{}, nil
 	}
-	return a.accessToken, nil
+	return t, nil
 }
+
+//SetAccessTokenStore set store which access token is saved in.
+//Access token will be saved in memory if store is not set.
+func (a *App) SetAccessTokenStore(s wechattoken.Store) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.store = s
+}
+
+//AccessTokenKey return key by which access token is saved in store.
+func (a *App) AccessTokenKey() string {
+	return "wechatmp/" + a.AppID
+}
+
+func (a *App) getStore() wechattoken.Store {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.store == nil {
+		a.store = wechattoken.NewMemoryStore()
+	}
+	return a.store
+}
+
+//saveAccessToken save granted token to store if current token in store is still old.
+//Token in store will be returned if it has been replaced by others.
+func (a *App) saveAccessToken(old string, token *wechattoken.Token) (*wechattoken.Token, error) {
+	return a.saveToken(a.AccessTokenKey(), old, token)
+}
+
+//saveToken save token to store by key if current token in store is still old.
+//Token in store will be returned if it has been replaced by others.
+func (a *App) saveToken(key string, old string, token *wechattoken.Token) (*wechattoken.Token, error) {
+	store := a.getStore()
+	ok, err := store.CompareAndSet(key, old, token)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return token, nil
+	}
+	current, err := store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsEmpty() {
+		return token, nil
+	}
+	return current, nil
+}
+
 func (a *App) ClientCredentialBuilder() fetcher.Command {
 	return fetcher.ParamsBuilderFunc(func(params url.Values) error {
 		params.Set("appid", a.AppID)
@@ -80,20 +184,36 @@ func (a *App) AuthorizationCodeBuilder(code string) fetcher.Command {
 	})
 }
 
-func (a *App) getRemoteToken() (string, error) {
-	t, err := a.loadAccessToken()
-	if err != nil {
-		return "", err
+//getRemoteToken get access token from remote refresher.
+//Current token will be sent as old token if rejected is empty.
+func (a *App) getRemoteToken(rejected string) (string, error) {
+	old := rejected
+	if old == "" {
+		t, err := a.loadAccessToken()
+		if err != nil {
+			return "", err
+		}
+		old = t.Value
 	}
-	data, err := sharedrefresherapi.FetcherRefreshShared(a.RemoteRefresher, []byte(t))
+	data, err := sharedrefresherapi.FetcherRefreshShared(a.RemoteRefresher, []byte(old))
 	if err != nil {
 		return "", err
 	}
 	return string(data), nil
 }
-func (a *App) GetAccessToken() (string, error) {
+
+//requestAccessToken request access token with current mode.
+//Rejected is token which should not be returned,which is ignored in classic mode.
+func (a *App) requestAccessToken(rejected string) (*wechattoken.Token, error) {
 	if !a.RemoteRefresher.IsEmpty() {
-		return a.getRemoteToken()
+		t, err := a.getRemoteToken(rejected)
+		if err != nil {
+			return nil, err
+		}
+		return wechattoken.NewToken(t, 0), nil
+	}
+	if a.AccessTokenMode == AccessTokenModeStable {
+		return a.requestStableAccessToken(rejected)
 	}
 	result := &resultAccessToken{}
 	resp, err := fetcher.DoAndParse(
@@ -102,75 +222,251 @@ func (a *App) GetAccessToken() (string, error) {
 		fetcher.Should200(fetcher.AsJSON(result)),
 	)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if result.Errcode != 0 || result.Errmsg != "" || result.AccessToken == "" {
-		return "", resp.NewAPICodeErr(result.Errcode)
+		return nil, resp.NewAPICodeErr(result.Errcode)
 	}
-	return result.AccessToken, nil
+	return wechattoken.NewToken(result.AccessToken, result.ExpiresIn), nil
 }
 
+//requestStableAccessToken request stable access token without force refresh.
+//Force refresh will be used only if rejected token is returned.
+func (a *App) requestStableAccessToken(rejected string) (*wechattoken.Token, error) {
+	t, err := a.fetchStableAccessToken(false)
+	if err != nil {
+		return nil, err
+	}
+	if rejected == "" || t.Value != rejected {
+		return t, nil
+	}
+	return a.fetchStableAccessToken(true)
+}
+
+func (a *App) fetchStableAccessToken(forceRefresh bool) (*wechattoken.Token, error) {
+	params := &paramsStableToken{
+		GrantType:    "client_credential",
+		AppID:        a.AppID,
+		Secret:       a.AppSecret,
+		ForceRefresh: forceRefresh,
+	}
+	result := &resultAccessToken{}
+	resp, err := fetcher.DoAndParse(
+		&a.Client,
+		APIStableToken.With(fetcher.JSONBody(params)),
+		fetcher.Should200(fetcher.AsJSON(result)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.Errcode != 0 || result.AccessToken == "" {
+		return nil, resp.NewAPICodeErr(result.Errcode)
+	}
+	return wechattoken.NewToken(result.AccessToken, result.ExpiresIn), nil
+}
+
+//GetStableAccessToken get access token from stable token api.
+//New token will be created if forceRefresh is true,and the old one will be invalidated.
+func (a *App) GetStableAccessToken(forceRefresh bool) (string, error) {
+	t, err := a.fetchStableAccessToken(forceRefresh)
+	if err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
+
+func (a *App) GetAccessToken() (string, error) {
+	t, err := a.requestAccessToken("")
+	if err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
+
+//GrantAccessToken grant new access token and save it to store.
 func (a *App) GrantAccessToken() (string, error) {
-	var token string
-	var err error
-	a.lock.Lock()
-	defer a.lock.Unlock()
-	token, err = a.loadAccessToken()
+	a.grantLock.Lock()
+	defer a.grantLock.Unlock()
+	t, err := a.loadAccessToken()
 	if err != nil {
 		return "", err
 	}
-	if a.accessTokenRefresher == nil {
-		token, err = a.GetAccessToken()
-	} else {
-		token, err = a.accessTokenRefresher(token)
+	t, err = a.grantAccessToken(t.Value)
+	if err != nil {
+		return "", err
 	}
+	return t.Value, nil
+}
 
+//RefreshAccessToken refresh access token which is rejected with given old value.
+//Current token will be returned without granting if old token has already been replaced.
+func (a *App) RefreshAccessToken(old string) (string, error) {
+	t, err := a.refreshAccessToken(old)
 	if err != nil {
 		return "", err
 	}
-	a.accessToken = token
-	return token, nil
+	return t.Value, nil
 }
 
-func (a *App) callApiWithAccessToken(api *fetcher.Preset, APIPresetBuilder func(accesstoken string) (*fetcher.Preset, error), v interface{}) error {
-	var apierr = &ResultAPIError{}
-	var err error
-	token, err := a.AccessToken()
+func (a *App) refreshAccessToken(old string) (*wechattoken.Token, error) {
+	a.grantLock.Lock()
+	defer a.grantLock.Unlock()
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	if t.Value != old && !t.IsExpired(time.Now()) {
+		return t, nil
+	}
+	return a.grantAccessToken(old)
+}
+
+//grantAccessToken grant new access token.
+//Rejected is token which should not be returned again,and is passed to refresher and provider as old token.
+//Current token will be used as old token if rejected is empty.
+//Grant lock should be held by caller.
+func (a *App) grantAccessToken(rejected string) (*wechattoken.Token, error) {
+	var token *wechattoken.Token
+	current, err := a.loadAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	old := rejected
+	if old == "" {
+		old = current.Value
+	}
+	switch {
+	case a.accessTokenRefresher != nil:
+		var v string
+		v, err = a.accessTokenRefresher(old)
+		token = wechattoken.NewToken(v, 0)
+	case a.accessTokenProvider != nil:
+		token, err = a.accessTokenProvider.RefreshSharedToken(old)
+	default:
+		token, err = a.requestAccessToken(rejected)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return a.saveAccessToken(current.Value, token)
+}
+
+//RefreshAccessTokenIfNeeded grant new access token if current token is empty or about to expire.
+func (a *App) RefreshAccessTokenIfNeeded() error {
+	a.grantLock.Lock()
+	defer a.grantLock.Unlock()
+	t, err := a.loadAccessToken()
 	if err != nil {
 		return err
 	}
-	if token == "" {
-		token, err = a.GrantAccessToken()
+	a.lock.Lock()
+	ahead := a.getRefreshAhead()
+	a.lock.Unlock()
+	if !t.NeedRefresh(time.Now(), ahead) {
+		return nil
+	}
+	_, err = a.grantAccessToken("")
+	return err
+}
+
+//NewBackgroundRefresher create background refresher which checks access token in given interval
+//and refreshes it before expired.
+func (a *App) NewBackgroundRefresher(interval time.Duration) *wechattoken.BackgroundRefresher {
+	return wechattoken.NewBackgroundRefresher(interval, a.RefreshAccessTokenIfNeeded)
+}
+
+func (a *App) refreshInBackground() {
+	a.RefreshAccessTokenIfNeeded()
+	a.lock.Lock()
+	a.refreshing = false
+	a.lock.Unlock()
+}
+
+//validAccessToken return current access token.
+//New token will be granted if current token is empty or expired.
+//Refresh will be started in background if current token is about to expire.
+func (a *App) validAccessToken() (string, error) {
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if t.IsExpired(now) {
+		t, err = a.refreshAccessToken(t.Value)
 		if err != nil {
-			return err
+			return "", err
 		}
+		return t.Value, nil
 	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if t.NeedRefresh(now, a.getRefreshAhead()) && !a.refreshing {
+		a.refreshing = true
+		go a.refreshInBackground()
+	}
+	return t.Value, nil
+}
+
+//apiErrorParser parser which parses api error from json response.
+//Non-json response such as media content will be ignored.
+type apiErrorParser struct {
+	result *ResultAPIError
+}
+
+func (p *apiErrorParser) Parse(resp *fetcher.Response) error {
+	body := bytes.TrimSpace(resp.BodyContent)
+	if len(body) == 0 || body[0] != '{' {
+		return nil
+	}
+	return fetcher.AsJSON(p.result).Parse(resp)
+}
+
+func (a *App) fetchPreset(APIPresetBuilder func(accesstoken string) (*fetcher.Preset, error), token string) (*fetcher.Response, *ResultAPIError, error) {
+	var apierr = &ResultAPIError{}
 	preset, err := APIPresetBuilder(token)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	resp, err := fetcher.DoAndParse(&a.Client, preset, fetcher.Should200(fetcher.AsJSON(apierr)))
+	resp, err := fetcher.DoAndParse(&a.Client, preset, fetcher.Should200(&apiErrorParser{result: apierr}))
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	if !apierr.IsOK() {
-		if apierr.IsAccessTokenError() {
-			token, err = a.GrantAccessToken()
-			if err != nil {
-				return err
-			}
-			apierr = &ResultAPIError{}
-			resp, err = fetcher.DoAndParse(&a.Client, preset, fetcher.Should200(fetcher.AsJSON(apierr)))
-			if err != nil {
-				return err
-			}
-			if !apierr.IsOK() {
-				return resp.NewAPICodeErr(apierr.Errcode)
-			}
-		} else {
-			return resp.NewAPICodeErr(apierr.Errcode)
+	return resp, apierr, nil
+}
+
+//fetchWithAccessToken fetch preset built with access token.
+//Preset will be rebuilt with refreshed token and fetched again if access token is rejected.
+func (a *App) fetchWithAccessToken(APIPresetBuilder func(accesstoken string) (*fetcher.Preset, error)) (*fetcher.Response, error) {
+	token, err := a.validAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, apierr, err := a.fetchPreset(APIPresetBuilder, token)
+	if err != nil {
+		return nil, err
+	}
+	if apierr.IsAccessTokenError() {
+		token, err = a.RefreshAccessToken(token)
+		if err != nil {
+			return nil, err
+		}
+		resp, apierr, err = a.fetchPreset(APIPresetBuilder, token)
+		if err != nil {
+			return nil, err
 		}
 	}
+	if !apierr.IsOK() {
+		return nil, resp.NewAPICodeErr(apierr.Errcode)
+	}
+	return resp, nil
+}
+
+func (a *App) callApiWithAccessToken(api *fetcher.Preset, APIPresetBuilder func(accesstoken string) (*fetcher.Preset, error), v interface{}) error {
+	resp, err := a.fetchWithAccessToken(APIPresetBuilder)
+	if err != nil {
+		return err
+	}
 	return fetcher.AsJSON(v).Parse(resp)
 }
 
@@ -181,6 +477,91 @@ func (a *App) CallJSONApiWithAccessToken(api *fetcher.Preset, params url.Values,
 	return a.callApiWithAccessToken(api, jsonAPIPresetBuilder, v)
 }
 
+//DownloadApiWithAccessToken call api with access token and return raw response.
+//Response body could be media content or json.
+func (a *App) DownloadApiWithAccessToken(api *fetcher.Preset, params url.Values, body interface{}) (*fetcher.Response, error) {
+	jsonAPIPresetBuilder := func(accesstoken string) (*fetcher.Preset, error) {
+		p := api.With(fetcher.Params(params), fetcher.SetQuery("access_token", accesstoken))
+		if body != nil {
+			p = p.With(fetcher.JSONBody(body))
+		}
+		return p, nil
+	}
+	return a.fetchWithAccessToken(jsonAPIPresetBuilder)
+}
+
+//UploadApiWithAccessToken call upload api with access token.
+//File body is streamed as multipart form field "media" with given filename,along with given form fields.
+//Body should implement io.Seeker to be resent when access token is rejected.
+func (a *App) UploadApiWithAccessToken(api *fetcher.Preset, params url.Values, form url.Values, filename string, body io.Reader, v interface{}) error {
+	var offset int64
+	var reader *io.PipeReader
+	var written chan struct{}
+	//stopWriting close pipe and wait until writer goroutine exits,so body can be reused safely.
+	stopWriting := func() {
+		if reader != nil {
+			reader.Close()
+			<-written
+		}
+	}
+	defer stopWriting()
+	uploadAPIPresetBuilder := func(accesstoken string) (*fetcher.Preset, error) {
+		seeker, seekable := body.(io.Seeker)
+		if reader != nil {
+			stopWriting()
+			if !seekable {
+				return nil, ErrUploadBodyNotSeekable
+			}
+			_, err := seeker.Seek(offset, io.SeekStart)
+			if err != nil {
+				return nil, err
+			}
+		} else if seekable {
+			o, err := seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return nil, err
+			}
+			offset = o
+		}
+		r, w := io.Pipe()
+		mw := multipart.NewWriter(w)
+		done := make(chan struct{})
+		reader = r
+		written = done
+		go func() {
+			defer close(done)
+			w.CloseWithError(writeMultipart(mw, form, filename, body))
+		}()
+		return api.With(
+			fetcher.Params(params),
+			fetcher.SetQuery("access_token", accesstoken),
+			fetcher.SetHeader("Content-Type", mw.FormDataContentType()),
+			fetcher.Body(r),
+		), nil
+	}
+	return a.callApiWithAccessToken(api, uploadAPIPresetBuilder, v)
+}
+
+func writeMultipart(mw *multipart.Writer, form url.Values, filename string, body io.Reader) error {
+	for k := range form {
+		for _, v := range form[k] {
+			err := mw.WriteField(k, v)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	filewriter, err := mw.CreateFormFile("media", filename)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(filewriter, body)
+	if err != nil {
+		return err
+	}
+	return mw.Close()
+}
+
 func (a *App) GetUserInfo(code string, scope string, lang string) (*Userinfo, error) {
 	var info = &Userinfo{}
 	if code == "" {
@@ -205,21 +586,29 @@ func (a *App) GetUserInfo(code string, scope string, lang string) (*Userinfo, er
 	if scope != ScopeSnsapiUserinfo {
 		return info, nil
 	}
+	err = a.loadUserDetail(info, lang)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (a *App) loadUserDetail(info *Userinfo, lang string) error {
 	var getuser = &resultUserDetail{}
-	resp, err = fetcher.DoAndParse(
+	resp, err := fetcher.DoAndParse(
 		&a.Client,
 		APIGetUserInfo.With(
-			fetcher.SetQuery("access_token", result.AccessToken),
-			fetcher.SetQuery("openid", result.OpenID),
+			fetcher.SetQuery("access_token", info.AccessToken),
+			fetcher.SetQuery("openid", info.OpenID),
 			fetcher.SetQuery("lang", lang),
 		),
 		fetcher.Should200(fetcher.AsJSON(getuser)),
 	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if getuser.Errcode != 0 {
-		return nil, resp.NewAPICodeErr(getuser.Errcode)
+		return resp.NewAPICodeErr(getuser.Errcode)
 	}
 
 	info.Nickname = getuser.Nickname
@@ -230,5 +619,98 @@ func (a *App) GetUserInfo(code string, scope string, lang string) (*Userinfo, er
 	info.HeadimgURL = getuser.HeadimgURL
 	info.Privilege = getuser.Privilege
 	info.UnionID = getuser.UnionID
+	return nil
+}
+
+//GetUserInfoByAccessToken get user info with user access token and openid.
+//Scope of access token should be snsapi_userinfo.
+func (a *App) GetUserInfoByAccessToken(accessToken string, openID string, lang string) (*Userinfo, error) {
+	info := &Userinfo{
+		OpenID:      openID,
+		AccessToken: accessToken,
+	}
+	err := a.loadUserDetail(info, lang)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+//RefreshUserAccessToken refresh user access token with refresh token.
+//Userinfo with new access token and refresh token will be returned.
+//Error checked by IsErrorRefreshTokenOutOfDate will be returned if refresh token is out of date.
+func (a *App) RefreshUserAccessToken(refreshToken string) (*Userinfo, error) {
+	var result = &resultOauthToken{}
+	resp, err := fetcher.DoAndParse(
+		&a.Client,
+		APIOauth2RefreshToken.With(
+			fetcher.SetQuery("appid", a.AppID),
+			fetcher.SetQuery("grant_type", "refresh_token"),
+			fetcher.SetQuery("refresh_token", refreshToken),
+		),
+		fetcher.Should200(fetcher.AsJSON(result)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.Errcode != 0 || result.AccessToken == "" {
+		return nil, resp.NewAPICodeErr(result.Errcode)
+	}
+	info := &Userinfo{
+		OpenID:       result.OpenID,
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		UnionID:      result.UnionID,
+	}
 	return info, nil
 }
+
+//ValidateUserAccessToken check if user access token is valid for given openid.
+func (a *App) ValidateUserAccessToken(accessToken string, openID string) (bool, error) {
+	var result = &ResultAPIError{}
+	resp, err := fetcher.DoAndParse(
+		&a.Client,
+		APISnsAuth.With(
+			fetcher.SetQuery("access_token", accessToken),
+			fetcher.SetQuery("openid", openID),
+		),
+		fetcher.Should200(fetcher.AsJSON(result)),
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.IsOK() {
+		return true, nil
+	}
+	if result.IsAccessTokenError() || result.Errcode == APIErrOpenIDWrong {
+		return false, nil
+	}
+	return false, resp.NewAPICodeErr(result.Errcode)
+}
+
+//RefreshUserInfo re-fetch user info with access token stored in given userinfo.
+//Access token will be refreshed with stored refresh token first if it is invalid.
+//Userinfo with latest tokens will be returned.
+//Error checked by IsErrorRefreshTokenOutOfDate will be returned if user should be authorized again.
+func (a *App) RefreshUserInfo(info *Userinfo, lang string) (*Userinfo, error) {
+	ok, err := a.ValidateUserAccessToken(info.AccessToken, info.OpenID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		refreshed, err := a.RefreshUserAccessToken(info.RefreshToken)
+		if err != nil {
+			return nil, err
+		}
+		info = refreshed
+	}
+	result, err := a.GetUserInfoByAccessToken(info.AccessToken, info.OpenID, lang)
+	if err != nil {
+		return nil, err
+	}
+	result.RefreshToken = info.RefreshToken
+	if result.UnionID == "" {
+		result.UnionID = info.UnionID
+	}
+	return result, nil
+}
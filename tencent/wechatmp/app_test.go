@@ -0,0 +1,288 @@
+package wechatmp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechattoken"
+)
+
+type testProvider struct {
+	lock sync.Mutex
+	olds []string
+}
+
+func (p *testProvider) RefreshSharedToken(old string) (*wechattoken.Token, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.olds = append(p.olds, old)
+	return wechattoken.NewToken("new", 7200), nil
+}
+
+func newTestAPIServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "new" {
+			w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+			return
+		}
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok","value":"result"}`))
+	}))
+}
+
+func newTestApp(token string) *App {
+	app := &App{AppID: "appid", AppSecret: "secret"}
+	store := wechattoken.NewMemoryStore()
+	store.CompareAndSet(app.AccessTokenKey(), "", wechattoken.NewToken(token, 7200))
+	app.SetAccessTokenStore(store)
+	return app
+}
+
+func TestRejectedTokenPassedToProvider(t *testing.T) {
+	s := newTestAPIServer()
+	defer s.Close()
+	api := fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint("POST", "/api")
+	app := newTestApp("old")
+	provider := &testProvider{}
+	app.SetAccessTokenProvider(provider)
+	result := &struct {
+		Value string `json:"value"`
+	}{}
+	err := app.CallJSONApiWithAccessToken(api, nil, nil, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Value != "result" {
+		t.Fatal(result)
+	}
+	if len(provider.olds) != 1 || provider.olds[0] != "old" {
+		t.Fatal(provider.olds)
+	}
+	token, err := app.AccessToken()
+	if err != nil || token != "new" {
+		t.Fatal(token, err)
+	}
+}
+
+func TestRejectedTokenPassedToRefresher(t *testing.T) {
+	s := newTestAPIServer()
+	defer s.Close()
+	api := fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint("POST", "/api")
+	app := newTestApp("old")
+	var olds []string
+	app.SetAccessTokenRefresher(func(old string) (string, error) {
+		olds = append(olds, old)
+		return "new", nil
+	})
+	err := app.CallJSONApiWithAccessToken(api, nil, nil, &ResultAPIError{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(olds) != 1 || olds[0] != "old" {
+		t.Fatal(olds)
+	}
+}
+
+func TestStableTokenForceRefreshedWhenRejected(t *testing.T) {
+	var bodies []*paramsStableToken
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			panic(err)
+		}
+		params := &paramsStableToken{}
+		err = json.Unmarshal(data, params)
+		if err != nil {
+			panic(err)
+		}
+		bodies = append(bodies, params)
+		if params.ForceRefresh {
+			w.Write([]byte(`{"access_token":"new","expires_in":7200}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"old","expires_in":7200}`))
+	}))
+	defer tokenServer.Close()
+	apiStableToken := APIStableToken
+	defer func() {
+		APIStableToken = apiStableToken
+	}()
+	APIStableToken = fetcher.MustPreset(&fetcher.ServerInfo{URL: tokenServer.URL}).EndPoint("POST", "/cgi-bin/stable_token")
+	s := newTestAPIServer()
+	defer s.Close()
+	api := fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint("POST", "/api")
+	app := newTestApp("old")
+	app.AccessTokenMode = AccessTokenModeStable
+	err := app.CallJSONApiWithAccessToken(api, nil, nil, &ResultAPIError{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bodies) != 2 || bodies[0].ForceRefresh || !bodies[1].ForceRefresh {
+		t.Fatal(bodies)
+	}
+	if bodies[1].AppID != "appid" || bodies[1].Secret != "secret" || bodies[1].GrantType != "client_credential" {
+		t.Fatal(bodies[1])
+	}
+}
+
+func TestConcurrentRejectedTokenGrantedOnce(t *testing.T) {
+	var lock sync.Mutex
+	var grants int
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		grants++
+		lock.Unlock()
+		w.Write([]byte(`{"access_token":"new","expires_in":7200}`))
+	}))
+	defer tokenServer.Close()
+	apiToken := APIToken
+	defer func() {
+		APIToken = apiToken
+	}()
+	APIToken = fetcher.MustPreset(&fetcher.ServerInfo{URL: tokenServer.URL}).EndPoint("GET", "/cgi-bin/token")
+	s := newTestAPIServer()
+	defer s.Close()
+	api := fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint("POST", "/api")
+	app := newTestApp("old")
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- app.CallJSONApiWithAccessToken(api, nil, nil, &ResultAPIError{})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if grants != 1 {
+		t.Fatal(grants)
+	}
+}
+
+func replaceEndPoint(t *testing.T, api **fetcher.Preset, s *httptest.Server, method string, path string) {
+	old := *api
+	*api = fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint(method, path)
+	t.Cleanup(func() {
+		*api = old
+	})
+}
+
+func newTestUserServer(refreshResult string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/sns/auth":
+			if q.Get("access_token") != "useraccesstoken" || q.Get("openid") != "openid" {
+				w.Write([]byte(`{"errcode":42001,"errmsg":"access_token expired"}`))
+				return
+			}
+			w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+		case "/sns/oauth2/refresh_token":
+			if q.Get("appid") != "appid" || q.Get("grant_type") != "refresh_token" || q.Get("refresh_token") != "refreshtoken" {
+				w.Write([]byte(`{"errcode":40030,"errmsg":"invalid refresh_token"}`))
+				return
+			}
+			w.Write([]byte(refreshResult))
+		case "/sns/userinfo":
+			if q.Get("access_token") != "useraccesstoken" || q.Get("openid") != "openid" {
+				w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+				return
+			}
+			w.Write([]byte(`{"openid":"openid","nickname":"nickname","unionid":"unionid"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestRefreshUserInfo(t *testing.T) {
+	s := newTestUserServer(`{"access_token":"useraccesstoken","expires_in":7200,"refresh_token":"refreshtoken","openid":"openid","scope":"snsapi_userinfo"}`)
+	defer s.Close()
+	replaceEndPoint(t, &APISnsAuth, s, "GET", "/sns/auth")
+	replaceEndPoint(t, &APIOauth2RefreshToken, s, "GET", "/sns/oauth2/refresh_token")
+	replaceEndPoint(t, &APIGetUserInfo, s, "GET", "/sns/userinfo")
+	app := newTestApp("token")
+	ok, err := app.ValidateUserAccessToken("useraccesstoken", "openid")
+	if !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	ok, err = app.ValidateUserAccessToken("expired", "openid")
+	if ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	info, err := app.RefreshUserInfo(&Userinfo{OpenID: "openid", AccessToken: "expired", RefreshToken: "refreshtoken"}, "zh_CN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.AccessToken != "useraccesstoken" || info.RefreshToken != "refreshtoken" || info.Nickname != "nickname" || info.UnionID != "unionid" {
+		t.Fatal(info)
+	}
+	_, err = app.RefreshUserAccessToken("wrong")
+	if err == nil || IsErrorRefreshTokenOutOfDate(err) {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshUserInfoRefreshTokenOutOfDate(t *testing.T) {
+	s := newTestUserServer(`{"errcode":42002,"errmsg":"refresh_token expired"}`)
+	defer s.Close()
+	replaceEndPoint(t, &APISnsAuth, s, "GET", "/sns/auth")
+	replaceEndPoint(t, &APIOauth2RefreshToken, s, "GET", "/sns/oauth2/refresh_token")
+	replaceEndPoint(t, &APIGetUserInfo, s, "GET", "/sns/userinfo")
+	app := newTestApp("token")
+	_, err := app.RefreshUserInfo(&Userinfo{OpenID: "openid", AccessToken: "expired", RefreshToken: "refreshtoken"}, "zh_CN")
+	if !IsErrorRefreshTokenOutOfDate(err) {
+		t.Fatal(err)
+	}
+}
+
+func TestUploadResentWhenRejectedBeforeBodyRead(t *testing.T) {
+	content := bytes.Repeat([]byte("content"), 1000000)
+	var lock sync.Mutex
+	var received []int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "new" {
+			w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+			return
+		}
+		err := r.ParseMultipartForm(1 << 20)
+		if err != nil {
+			panic(err)
+		}
+		f, _, err := r.FormFile("media")
+		if err != nil {
+			panic(err)
+		}
+		defer f.Close()
+		data, err := ioutil.ReadAll(f)
+		if err != nil {
+			panic(err)
+		}
+		lock.Lock()
+		received = append(received, len(data))
+		lock.Unlock()
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer s.Close()
+	api := fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint("POST", "/upload")
+	app := newTestApp("old")
+	app.SetAccessTokenProvider(&testProvider{})
+	err := app.UploadApiWithAccessToken(api, nil, nil, "file", bytes.NewReader(content), &ResultAPIError{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 || received[0] != len(content) {
+		t.Fatal(received)
+	}
+}
@@ -0,0 +1,184 @@
+package broadcast
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//SendOpenIDLimit max openid count of one send request.
+const SendOpenIDLimit = 10000
+
+//SendOpenIDMin min openid count of one send request.
+const SendOpenIDMin = 2
+
+const StatusSendSuccess = "SEND_SUCCESS"
+const StatusSending = "SENDING"
+const StatusSendFail = "SEND_FAIL"
+const StatusDelete = "DELETE"
+
+//ErrTooFewOpenIDs error raised when sending to less than SendOpenIDMin openids.
+var ErrTooFewOpenIDs = errors.New("broadcast: at least 2 openids required")
+
+//SendResult broadcast send result
+type SendResult struct {
+	MsgID     int64 `json:"msg_id"`
+	MsgDataID int64 `json:"msg_data_id"`
+}
+
+//Status broadcast status
+type Status struct {
+	MsgID     int64  `json:"msg_id"`
+	MsgStatus string `json:"msg_status"`
+}
+
+//Filter broadcast filter
+type Filter struct {
+	IsToAll bool `json:"is_to_all"`
+	//TagID tag id of followers to send to.
+	//TagID is always sent,as 0 is a valid tag id.
+	TagID int `json:"tag_id"`
+}
+
+type paramsSendAll struct {
+	Filter *Filter `json:"filter"`
+	*Content
+}
+
+type paramsSend struct {
+	ToUser []string `json:"touser"`
+	*Content
+	ClientMsgID string `json:"clientmsgid,omitempty"`
+}
+
+type paramsPreview struct {
+	ToUser   string `json:"touser,omitempty"`
+	ToWxName string `json:"towxname,omitempty"`
+	*Content
+}
+
+type paramsDelete struct {
+	MsgID      int64 `json:"msg_id"`
+	ArticleIdx int   `json:"article_idx,omitempty"`
+}
+
+type paramsMsgID struct {
+	MsgID int64 `json:"msg_id"`
+}
+
+func sendAll(App *wechatmp.App, f *Filter, c *Content) (*SendResult, error) {
+	params := &paramsSendAll{
+		Filter:  f,
+		Content: c,
+	}
+	result := &SendResult{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIMessageMassSendAll, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//SendToAll broadcast content to all followers.
+func SendToAll(App *wechatmp.App, c *Content) (*SendResult, error) {
+	return sendAll(App, &Filter{IsToAll: true}, c)
+}
+
+//SendByTag broadcast content to followers with given tag.
+func SendByTag(App *wechatmp.App, tagid int, c *Content) (*SendResult, error) {
+	return sendAll(App, &Filter{TagID: tagid}, c)
+}
+
+//SplitOpenIDs split openids into chunks with at most limit openids.
+//Chunks are balanced so that every chunk has at least SendOpenIDMin openids if possible.
+func SplitOpenIDs(openids []string, limit int) [][]string {
+	result := [][]string{}
+	for start := 0; start < len(openids); {
+		end := start + limit
+		if end > len(openids) {
+			end = len(openids)
+		}
+		rest := len(openids) - end
+		if rest > 0 && rest < SendOpenIDMin && end-start-(SendOpenIDMin-rest) >= SendOpenIDMin {
+			end = end - (SendOpenIDMin - rest)
+		}
+		result = append(result, openids[start:end])
+		start = end
+	}
+	return result
+}
+
+//SendByOpenIDs broadcast content to given openids.
+//Openids will be split into requests with at most SendOpenIDLimit openids.
+//If content has ClientMsgID and more than one request is sent,
+//chunk index will be appended to ClientMsgID of each request,like "msgid-0","msgid-1".
+//Results of sent requests will be returned even if error raised.
+func SendByOpenIDs(App *wechatmp.App, openids []string, c *Content) ([]*SendResult, error) {
+	if len(openids) < SendOpenIDMin {
+		return nil, ErrTooFewOpenIDs
+	}
+	results := []*SendResult{}
+	chunks := SplitOpenIDs(openids, SendOpenIDLimit)
+	for k, chunk := range chunks {
+		params := &paramsSend{
+			ToUser:      chunk,
+			Content:     c,
+			ClientMsgID: c.ClientMsgID,
+		}
+		if c.ClientMsgID != "" && len(chunks) > 1 {
+			params.ClientMsgID = c.ClientMsgID + "-" + strconv.Itoa(k)
+		}
+		result := &SendResult{}
+		err := App.CallJSONApiWithAccessToken(wechatmp.APIMessageMassSend, nil, params, result)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+//Preview send preview of content to given openid.
+func Preview(App *wechatmp.App, openid string, c *Content) error {
+	params := &paramsPreview{
+		ToUser:  openid,
+		Content: c,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIMessageMassPreview, nil, params, result)
+}
+
+//PreviewByWxName send preview of content to given wechat id.
+func PreviewByWxName(App *wechatmp.App, wxname string, c *Content) error {
+	params := &paramsPreview{
+		ToWxName: wxname,
+		Content:  c,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIMessageMassPreview, nil, params, result)
+}
+
+//Delete delete sent broadcast by msg id.
+//Article index starts from 1,and all articles will be deleted if articleIdx is 0.
+func Delete(App *wechatmp.App, msgID int64, articleIdx int) error {
+	params := &paramsDelete{
+		MsgID:      msgID,
+		ArticleIdx: articleIdx,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIMessageMassDelete, nil, params, result)
+}
+
+//GetStatus get status of broadcast by msg id.
+func GetStatus(App *wechatmp.App, msgID int64) (*Status, error) {
+	params := &paramsMsgID{
+		MsgID: msgID,
+	}
+	result := &Status{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIMessageMassGet, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
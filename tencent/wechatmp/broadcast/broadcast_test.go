@@ -0,0 +1,185 @@
+package broadcast
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/internal/wechatmptest"
+)
+
+func testOpenIDs(count int) []string {
+	result := make([]string, count)
+	for k := range result {
+		result[k] = strconv.Itoa(k)
+	}
+	return result
+}
+
+func TestSplitOpenIDs(t *testing.T) {
+	var tests = []struct {
+		count  int
+		limit  int
+		chunks []int
+	}{
+		{0, 10, []int{}},
+		{2, 10, []int{2}},
+		{10, 10, []int{10}},
+		{11, 10, []int{9, 2}},
+		{12, 10, []int{10, 2}},
+		{25, 10, []int{10, 10, 5}},
+		{21, 10, []int{10, 9, 2}},
+		{3, 2, []int{2, 1}},
+	}
+	for _, test := range tests {
+		openids := testOpenIDs(test.count)
+		chunks := SplitOpenIDs(openids, test.limit)
+		if len(chunks) != len(test.chunks) {
+			t.Fatal(test, chunks)
+		}
+		total := 0
+		for k := range chunks {
+			if len(chunks[k]) != test.chunks[k] {
+				t.Fatal(test, chunks)
+			}
+			for _, v := range chunks[k] {
+				if v != openids[total] {
+					t.Fatal(test, chunks)
+				}
+				total++
+			}
+		}
+		if total != test.count {
+			t.Fatal(test, chunks)
+		}
+	}
+}
+
+func TestParseMassSendJobFinishEvent(t *testing.T) {
+	content := []byte(`<xml>
+<ToUserName><![CDATA[gh_4d00ed8d6399]]></ToUserName>
+<FromUserName><![CDATA[oV5CrjpxgaGXNHIQigzNlgLTnwic]]></FromUserName>
+<CreateTime>1481013459</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[MASSSENDJOBFINISH]]></Event>
+<MsgID>1000001625</MsgID>
+<Status><![CDATA[err(30003)]]></Status>
+<TotalCount>0</TotalCount>
+<FilterCount>0</FilterCount>
+<SentCount>0</SentCount>
+<ErrorCount>0</ErrorCount>
+<CopyrightCheckResult>
+<Count>2</Count>
+<ResultList>
+<item>
+<ArticleIdx>1</ArticleIdx>
+<UserDeclareState>0</UserDeclareState>
+<AuditState>2</AuditState>
+<OriginalArticleUrl><![CDATA[Url_1]]></OriginalArticleUrl>
+<OriginalArticleType>1</OriginalArticleType>
+<CanReprint>1</CanReprint>
+<NeedReplaceContent>1</NeedReplaceContent>
+<NeedShowReprintSource>1</NeedShowReprintSource>
+</item>
+<item>
+<ArticleIdx>2</ArticleIdx>
+<UserDeclareState>0</UserDeclareState>
+<AuditState>2</AuditState>
+<OriginalArticleUrl><![CDATA[Url_2]]></OriginalArticleUrl>
+<OriginalArticleType>1</OriginalArticleType>
+<CanReprint>1</CanReprint>
+<NeedReplaceContent>1</NeedReplaceContent>
+<NeedShowReprintSource>1</NeedShowReprintSource>
+</item>
+</ResultList>
+<CheckState>2</CheckState>
+</CopyrightCheckResult>
+<ArticleUrlResult>
+<Count>1</Count>
+<ResultList>
+<item>
+<ArticleIdx>1</ArticleIdx>
+<ArticleUrl><![CDATA[Url]]></ArticleUrl>
+</item>
+</ResultList>
+</ArticleUrlResult>
+</xml>`)
+	e, err := ParseMassSendJobFinishEvent(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Event != EventMassSendJobFinish || e.MsgID != 1000001625 || e.Status != "err(30003)" {
+		t.Fatal(e)
+	}
+	if e.CopyrightCheckResult.Count != 2 || len(e.CopyrightCheckResult.ResultList) != 2 || e.CopyrightCheckResult.ResultList[1].OriginalArticleURL != "Url_2" || e.CopyrightCheckResult.CheckState != 2 {
+		t.Fatal(e.CopyrightCheckResult)
+	}
+	if len(e.ArticleURLResult.ResultList) != 1 || e.ArticleURLResult.ResultList[0].ArticleURL != "Url" {
+		t.Fatal(e.ArticleURLResult)
+	}
+}
+
+func TestSendByOpenIDs(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIMessageMassSend, "POST", "/cgi-bin/message/mass/send")
+	var msgid int64
+	s.Handle("/cgi-bin/message/mass/send", func(r *wechatmptest.Request) interface{} {
+		msgid++
+		return &SendResult{MsgID: msgid, MsgDataID: msgid}
+	})
+	c := NewContent(MsgTypeText)
+	c.Text.Content = "content"
+	c.ClientMsgID = "msgid"
+	results, err := SendByOpenIDs(s.NewApp(), testOpenIDs(SendOpenIDLimit+1), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].MsgID != 1 || results[1].MsgID != 2 {
+		t.Fatal(results)
+	}
+	ids := []string{}
+	for _, r := range s.Requests("/cgi-bin/message/mass/send") {
+		params := map[string]interface{}{}
+		err = r.Unmarshal(&params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, params["clientmsgid"].(string))
+	}
+	if strings.Join(ids, ",") != "msgid-0,msgid-1" || c.ClientMsgID != "msgid" {
+		t.Fatal(ids)
+	}
+	results, err = SendByOpenIDs(s.NewApp(), testOpenIDs(2), c)
+	if err != nil || len(results) != 1 {
+		t.Fatal(results, err)
+	}
+	params := map[string]interface{}{}
+	err = s.Requests("/cgi-bin/message/mass/send")[2].Unmarshal(&params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["clientmsgid"] != "msgid" {
+		t.Fatal(params)
+	}
+}
+
+func TestSendByTag(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIMessageMassSendAll, "POST", "/cgi-bin/message/mass/sendall")
+	s.Handle("/cgi-bin/message/mass/sendall", func(r *wechatmptest.Request) interface{} {
+		return &SendResult{MsgID: 1}
+	})
+	c := NewContent(MsgTypeText)
+	c.Text.Content = "content"
+	_, err := SendByTag(s.NewApp(), 0, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := s.Requests("/cgi-bin/message/mass/sendall")[0]
+	if !strings.Contains(string(r.Body), `"filter":{"is_to_all":false,"tag_id":0}`) {
+		t.Fatal(string(r.Body))
+	}
+}
@@ -0,0 +1,68 @@
+package broadcast
+
+const MsgTypeMPNews = "mpnews"
+const MsgTypeText = "text"
+const MsgTypeVoice = "voice"
+const MsgTypeImage = "image"
+const MsgTypeMPVideo = "mpvideo"
+const MsgTypeWxCard = "wxcard"
+
+//Content broadcast message content
+type Content struct {
+	MsgType string         `json:"msgtype"`
+	MPNews  *MessageMedia  `json:"mpnews,omitempty"`
+	Text    *MessageText   `json:"text,omitempty"`
+	Voice   *MessageMedia  `json:"voice,omitempty"`
+	Images  *MessageImages `json:"images,omitempty"`
+	MPVideo *MessageMedia  `json:"mpvideo,omitempty"`
+	WxCard  *MessageWxCard `json:"wxcard,omitempty"`
+	//SendIgnoreReprint whether to continue sending when article is judged as reprint.
+	SendIgnoreReprint int `json:"send_ignore_reprint"`
+	//ClientMsgID id used to avoid sending same message repeatedly.
+	ClientMsgID string `json:"clientmsgid,omitempty"`
+}
+
+//SetMsgType set message type and create empty content of given type.
+func (c *Content) SetMsgType(MsgType string) {
+	c.MsgType = MsgType
+	switch MsgType {
+	case MsgTypeMPNews:
+		c.MPNews = &MessageMedia{}
+	case MsgTypeText:
+		c.Text = &MessageText{}
+	case MsgTypeVoice:
+		c.Voice = &MessageMedia{}
+	case MsgTypeImage:
+		c.Images = &MessageImages{}
+	case MsgTypeMPVideo:
+		c.MPVideo = &MessageMedia{}
+	case MsgTypeWxCard:
+		c.WxCard = &MessageWxCard{}
+	}
+}
+
+type MessageMedia struct {
+	MediaID string `json:"media_id"`
+}
+
+type MessageText struct {
+	Content string `json:"content"`
+}
+
+type MessageImages struct {
+	MediaIDs           []string `json:"media_ids"`
+	Recommend          string   `json:"recommend,omitempty"`
+	NeedOpenComment    int      `json:"need_open_comment"`
+	OnlyFansCanComment int      `json:"only_fans_can_comment"`
+}
+
+type MessageWxCard struct {
+	CardID string `json:"card_id"`
+}
+
+//NewContent create new content with given type.
+func NewContent(MsgType string) *Content {
+	c := &Content{}
+	c.SetMsgType(MsgType)
+	return c
+}
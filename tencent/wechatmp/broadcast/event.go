@@ -0,0 +1,64 @@
+package broadcast
+
+import "encoding/xml"
+
+//EventMassSendJobFinish event sent when broadcast job finished.
+const EventMassSendJobFinish = "MASSSENDJOBFINISH"
+
+//CopyrightCheckItem copyright check result of article.
+type CopyrightCheckItem struct {
+	ArticleIdx            int
+	UserDeclareState      int
+	AuditState            int
+	OriginalArticleURL    string `xml:"OriginalArticleUrl"`
+	OriginalArticleType   int
+	CanReprint            int
+	NeedReplaceContent    int
+	NeedShowReprintSource int
+}
+
+//CopyrightCheckResult copyright check result of broadcast.
+type CopyrightCheckResult struct {
+	Count      int
+	ResultList []*CopyrightCheckItem `xml:"ResultList>item"`
+	CheckState int
+}
+
+//ArticleURLItem url of sent article.
+type ArticleURLItem struct {
+	ArticleIdx int
+	ArticleURL string `xml:"ArticleUrl"`
+}
+
+//ArticleURLResult urls of sent articles.
+type ArticleURLResult struct {
+	Count      int
+	ResultList []*ArticleURLItem `xml:"ResultList>item"`
+}
+
+//MassSendJobFinishEvent broadcast job finish event
+type MassSendJobFinishEvent struct {
+	ToUserName           string
+	FromUserName         string
+	CreateTime           int64
+	MsgType              string
+	Event                string
+	MsgID                int64
+	Status               string
+	TotalCount           int
+	FilterCount          int
+	SentCount            int
+	ErrorCount           int
+	CopyrightCheckResult *CopyrightCheckResult
+	ArticleURLResult     *ArticleURLResult `xml:"ArticleUrlResult"`
+}
+
+//ParseMassSendJobFinishEvent parse broadcast job finish event from receiver message content.
+func ParseMassSendJobFinishEvent(content []byte) (*MassSendJobFinishEvent, error) {
+	e := &MassSendJobFinishEvent{}
+	err := xml.Unmarshal(content, e)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
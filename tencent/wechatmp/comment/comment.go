@@ -0,0 +1,156 @@
+package comment
+
+import (
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+const TypeAll = 0
+const TypeNormal = 1
+const TypeElected = 2
+
+//MaxListCount max count of comments in one list request.
+const MaxListCount = 50
+
+//Reply author reply of comment.
+type Reply struct {
+	Content    string `json:"content"`
+	CreateTime int64  `json:"create_time"`
+}
+
+//Comment user comment of article.
+type Comment struct {
+	UserCommentID int64  `json:"user_comment_id"`
+	OpenID        string `json:"openid"`
+	CreateTime    int64  `json:"create_time"`
+	Content       string `json:"content"`
+	CommentType   int    `json:"comment_type"`
+	Reply         *Reply `json:"reply"`
+}
+
+//List comment list
+type List struct {
+	Total   int        `json:"total"`
+	Comment []*Comment `json:"comment"`
+}
+
+type paramsArticle struct {
+	MsgDataID int64 `json:"msg_data_id"`
+	Index     int   `json:"index"`
+}
+
+type paramsList struct {
+	MsgDataID int64 `json:"msg_data_id"`
+	Index     int   `json:"index"`
+	Begin     int   `json:"begin"`
+	Count     int   `json:"count"`
+	Type      int   `json:"type"`
+}
+
+type paramsComment struct {
+	MsgDataID     int64 `json:"msg_data_id"`
+	Index         int   `json:"index"`
+	UserCommentID int64 `json:"user_comment_id"`
+}
+
+type paramsReply struct {
+	MsgDataID     int64  `json:"msg_data_id"`
+	Index         int    `json:"index"`
+	UserCommentID int64  `json:"user_comment_id"`
+	Content       string `json:"content"`
+}
+
+func callArticle(App *wechatmp.App, api *fetcher.Preset, msgDataID int64, index int) error {
+	params := &paramsArticle{
+		MsgDataID: msgDataID,
+		Index:     index,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(api, nil, params, result)
+}
+
+func callComment(App *wechatmp.App, api *fetcher.Preset, msgDataID int64, index int, userCommentID int64) error {
+	params := &paramsComment{
+		MsgDataID:     msgDataID,
+		Index:         index,
+		UserCommentID: userCommentID,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(api, nil, params, result)
+}
+
+//Open open comment of article.
+//Msg data id is returned by broadcast or publish,and index starts from 0.
+func Open(App *wechatmp.App, msgDataID int64, index int) error {
+	return callArticle(App, wechatmp.APICommentOpen, msgDataID, index)
+}
+
+//Close close comment of article.
+func Close(App *wechatmp.App, msgDataID int64, index int) error {
+	return callArticle(App, wechatmp.APICommentClose, msgDataID, index)
+}
+
+//GetList get comments of article with given comment type.
+//Count should be between 1 and MaxListCount.
+func GetList(App *wechatmp.App, msgDataID int64, index int, begin int, count int, commentType int) (*List, error) {
+	params := &paramsList{
+		MsgDataID: msgDataID,
+		Index:     index,
+		Begin:     begin,
+		Count:     count,
+		Type:      commentType,
+	}
+	result := &List{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APICommentList, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetAll get all comments of article with given comment type page by page.
+func GetAll(App *wechatmp.App, msgDataID int64, index int, commentType int) ([]*Comment, error) {
+	result := []*Comment{}
+	for {
+		list, err := GetList(App, msgDataID, index, len(result), MaxListCount, commentType)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, list.Comment...)
+		if len(list.Comment) == 0 || len(result) >= list.Total {
+			return result, nil
+		}
+	}
+}
+
+//MarkElect mark comment as elected.
+func MarkElect(App *wechatmp.App, msgDataID int64, index int, userCommentID int64) error {
+	return callComment(App, wechatmp.APICommentMarkElect, msgDataID, index, userCommentID)
+}
+
+//UnmarkElect unmark elected comment.
+func UnmarkElect(App *wechatmp.App, msgDataID int64, index int, userCommentID int64) error {
+	return callComment(App, wechatmp.APICommentUnmarkElect, msgDataID, index, userCommentID)
+}
+
+//Delete delete comment.
+func Delete(App *wechatmp.App, msgDataID int64, index int, userCommentID int64) error {
+	return callComment(App, wechatmp.APICommentDelete, msgDataID, index, userCommentID)
+}
+
+//AddReply reply comment.
+func AddReply(App *wechatmp.App, msgDataID int64, index int, userCommentID int64, content string) error {
+	params := &paramsReply{
+		MsgDataID:     msgDataID,
+		Index:         index,
+		UserCommentID: userCommentID,
+		Content:       content,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APICommentReplyAdd, nil, params, result)
+}
+
+//DeleteReply delete reply of comment.
+func DeleteReply(App *wechatmp.App, msgDataID int64, index int, userCommentID int64) error {
+	return callComment(App, wechatmp.APICommentReplyDelete, msgDataID, index, userCommentID)
+}
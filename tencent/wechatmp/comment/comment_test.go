@@ -0,0 +1,212 @@
+package comment
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/internal/wechatmptest"
+)
+
+func TestGetAll(t *testing.T) {
+	var tests = []struct {
+		Name  string
+		Total int
+		//Stored count of comments which server actually returns.
+		Stored int
+		Begins []int
+	}{
+		{"empty", 0, 0, []int{0}},
+		{"one page", 30, 30, []int{0}},
+		{"full page", 50, 50, []int{0}},
+		{"pages", 120, 120, []int{0, 50, 100}},
+		{"fewer than total", 120, 60, []int{0, 50, 60}},
+	}
+	for _, v := range tests {
+		s := wechatmptest.NewServer()
+		s.Replace(t, &wechatmp.APICommentList, "POST", "/cgi-bin/comment/list")
+		total, stored := v.Total, v.Stored
+		s.Handle("/cgi-bin/comment/list", func(r *wechatmptest.Request) interface{} {
+			params := &paramsList{}
+			err := r.Unmarshal(params)
+			if err != nil {
+				panic(err)
+			}
+			list := &List{Total: total, Comment: []*Comment{}}
+			for i := params.Begin; i < params.Begin+params.Count && i < stored; i++ {
+				list.Comment = append(list.Comment, &Comment{UserCommentID: int64(i)})
+			}
+			return list
+		})
+		comments, err := GetAll(s.NewApp(), 2247483795, 1, TypeElected)
+		s.Close()
+		if err != nil {
+			t.Fatal(v.Name, err)
+		}
+		if len(comments) != v.Stored {
+			t.Fatal(v.Name, len(comments))
+		}
+		for k := range comments {
+			if comments[k].UserCommentID != int64(k) {
+				t.Fatal(v.Name, comments[k])
+			}
+		}
+		begins := []int{}
+		for _, r := range s.Requests("/cgi-bin/comment/list") {
+			params := &paramsList{}
+			err = r.Unmarshal(params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if params.MsgDataID != 2247483795 || params.Index != 1 || params.Count != MaxListCount || params.Type != TypeElected {
+				t.Fatal(v.Name, params)
+			}
+			begins = append(begins, params.Begin)
+		}
+		if !reflect.DeepEqual(begins, v.Begins) {
+			t.Fatal(v.Name, begins)
+		}
+	}
+}
+
+func TestGetAllError(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APICommentList, "POST", "/cgi-bin/comment/list")
+	s.Handle("/cgi-bin/comment/list", func(r *wechatmptest.Request) interface{} {
+		return &wechatmp.ResultAPIError{Errcode: 88000, Errmsg: "without comment privilege"}
+	})
+	_, err := GetAll(s.NewApp(), 2247483795, 1, TypeAll)
+	if !fetcher.CompareAPIErrCode(err, 88000) {
+		t.Fatal(err)
+	}
+}
+
+func TestGetList(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APICommentList, "POST", "/cgi-bin/comment/list")
+	s.Handle("/cgi-bin/comment/list", func(r *wechatmptest.Request) interface{} {
+		return []byte(`{"errcode":0,"errmsg":"ok","total":3,"comment":[{"user_comment_id":1,"openid":"openid","create_time":1600000000,"content":"comment","comment_type":1,"reply":{"content":"reply","create_time":1600000001}}]}`)
+	})
+	list, err := GetList(s.NewApp(), 2247483795, 1, 2, 1, TypeNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 3 || len(list.Comment) != 1 {
+		t.Fatal(list)
+	}
+	c := list.Comment[0]
+	if c.UserCommentID != 1 || c.OpenID != "openid" || c.CreateTime != 1600000000 || c.Content != "comment" || c.CommentType != TypeNormal {
+		t.Fatal(c)
+	}
+	if c.Reply == nil || *c.Reply != (Reply{Content: "reply", CreateTime: 1600000001}) {
+		t.Fatal(c.Reply)
+	}
+	params := &paramsList{}
+	err = s.Requests("/cgi-bin/comment/list")[0].Unmarshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *params != (paramsList{MsgDataID: 2247483795, Index: 1, Begin: 2, Count: 1, Type: TypeNormal}) {
+		t.Fatal(params)
+	}
+}
+
+func TestArticleAndCommentAPIs(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	paths := map[**fetcher.Preset]string{
+		&wechatmp.APICommentOpen:        "/cgi-bin/comment/open",
+		&wechatmp.APICommentClose:       "/cgi-bin/comment/close",
+		&wechatmp.APICommentMarkElect:   "/cgi-bin/comment/markelect",
+		&wechatmp.APICommentUnmarkElect: "/cgi-bin/comment/unmarkelect",
+		&wechatmp.APICommentDelete:      "/cgi-bin/comment/delete",
+		&wechatmp.APICommentReplyAdd:    "/cgi-bin/comment/reply/add",
+		&wechatmp.APICommentReplyDelete: "/cgi-bin/comment/reply/delete",
+	}
+	for api, path := range paths {
+		s.Replace(t, api, "POST", path)
+		s.Handle(path, func(r *wechatmptest.Request) interface{} {
+			params := &paramsArticle{}
+			err := r.Unmarshal(params)
+			if err != nil {
+				panic(err)
+			}
+			if params.MsgDataID != 2247483795 {
+				return &wechatmp.ResultAPIError{Errcode: 88000, Errmsg: "without comment privilege"}
+			}
+			return &wechatmp.ResultAPIError{}
+		})
+	}
+	app := s.NewApp()
+	var articleTests = []struct {
+		Path string
+		Call func(App *wechatmp.App, msgDataID int64, index int) error
+	}{
+		{"/cgi-bin/comment/open", Open},
+		{"/cgi-bin/comment/close", Close},
+	}
+	for _, v := range articleTests {
+		err := v.Call(app, 2247483795, 1)
+		if err != nil {
+			t.Fatal(v.Path, err)
+		}
+		err = v.Call(app, 1, 1)
+		if !fetcher.CompareAPIErrCode(err, 88000) {
+			t.Fatal(v.Path, err)
+		}
+		params := &paramsArticle{}
+		err = s.Requests(v.Path)[0].Unmarshal(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *params != (paramsArticle{MsgDataID: 2247483795, Index: 1}) {
+			t.Fatal(v.Path, params)
+		}
+	}
+	var commentTests = []struct {
+		Path string
+		Call func(App *wechatmp.App, msgDataID int64, index int, userCommentID int64) error
+	}{
+		{"/cgi-bin/comment/markelect", MarkElect},
+		{"/cgi-bin/comment/unmarkelect", UnmarkElect},
+		{"/cgi-bin/comment/delete", Delete},
+		{"/cgi-bin/comment/reply/delete", DeleteReply},
+	}
+	for _, v := range commentTests {
+		err := v.Call(app, 2247483795, 1, 10)
+		if err != nil {
+			t.Fatal(v.Path, err)
+		}
+		err = v.Call(app, 1, 1, 10)
+		if !fetcher.CompareAPIErrCode(err, 88000) {
+			t.Fatal(v.Path, err)
+		}
+		params := &paramsComment{}
+		err = s.Requests(v.Path)[0].Unmarshal(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *params != (paramsComment{MsgDataID: 2247483795, Index: 1, UserCommentID: 10}) {
+			t.Fatal(v.Path, params)
+		}
+	}
+	err := AddReply(app, 2247483795, 1, 10, "reply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = AddReply(app, 1, 1, 10, "reply")
+	if !fetcher.CompareAPIErrCode(err, 88000) {
+		t.Fatal(err)
+	}
+	params := &paramsReply{}
+	err = s.Requests("/cgi-bin/comment/reply/add")[0].Unmarshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *params != (paramsReply{MsgDataID: 2247483795, Index: 1, UserCommentID: 10, Content: "reply"}) {
+		t.Fatal(params)
+	}
+}
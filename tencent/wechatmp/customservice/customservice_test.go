@@ -0,0 +1,140 @@
+package customservice
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/internal/wechatmptest"
+)
+
+func TestMessage(t *testing.T) {
+	m := NewTextMessage("openid", "content")
+	m.SetKFAccount("test@wechatmp")
+	bs, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wanted := `{"touser":"openid","msgtype":"text","text":{"content":"content"},"customservice":{"kf_account":"test@wechatmp"}}`
+	if string(bs) != wanted {
+		t.Fatal(string(bs))
+	}
+	m = NewMessage("openid", MsgTypeMenu)
+	if m.Menu == nil || m.Text != nil {
+		t.Fatal(m)
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIMessageCustomSend, "POST", "/cgi-bin/message/custom/send")
+	s.Replace(t, &wechatmp.APIMessageCustomTyping, "POST", "/cgi-bin/message/custom/typing")
+	s.Handle("/cgi-bin/message/custom/send", func(r *wechatmptest.Request) interface{} {
+		m := &Message{}
+		err := r.Unmarshal(m)
+		if err != nil {
+			panic(err)
+		}
+		if m.ToUser != "openid" {
+			return &wechatmp.ResultAPIError{Errcode: 45015, Errmsg: "response out of time limit"}
+		}
+		return &wechatmp.ResultAPIError{}
+	})
+	s.Handle("/cgi-bin/message/custom/typing", func(r *wechatmptest.Request) interface{} {
+		return &wechatmp.ResultAPIError{}
+	})
+	app := s.NewApp()
+	err := SendMessage(app, NewTextMessage("openid", "content"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SendMessage(app, NewTextMessage("expired", "content"))
+	if !fetcher.CompareAPIErrCode(err, 45015) {
+		t.Fatal(err)
+	}
+	sent := &Message{}
+	err = s.Requests("/cgi-bin/message/custom/send")[0].Unmarshal(sent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.MsgType != MsgTypeText || sent.Text.Content != "content" {
+		t.Fatal(sent)
+	}
+	err = SetTyping(app, "openid", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SetTyping(app, "openid", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands := []string{}
+	for _, r := range s.Requests("/cgi-bin/message/custom/typing") {
+		params := &paramsTyping{}
+		err = r.Unmarshal(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		commands = append(commands, params.Command)
+	}
+	if len(commands) != 2 || commands[0] != TypingCommandTyping || commands[1] != TypingCommandCancelTyping {
+		t.Fatal(commands)
+	}
+}
+
+func TestKFAccountAndSession(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIKFList, "GET", "/cgi-bin/customservice/getkflist")
+	s.Replace(t, &wechatmp.APIKFSessionCreate, "POST", "/customservice/kfsession/create")
+	s.Replace(t, &wechatmp.APIKFSessionGet, "GET", "/customservice/kfsession/getsession")
+	s.Replace(t, &wechatmp.APIKFSessionWaitCase, "GET", "/customservice/kfsession/getwaitcase")
+	s.Handle("/cgi-bin/customservice/getkflist", func(r *wechatmptest.Request) interface{} {
+		return []byte(`{"kf_list":[{"kf_account":"test@wechatmp","kf_nick":"nick","kf_id":"1001","invite_status":"waiting","invite_expire_time":1600000000}]}`)
+	})
+	s.Handle("/customservice/kfsession/create", func(r *wechatmptest.Request) interface{} {
+		return &wechatmp.ResultAPIError{}
+	})
+	s.Handle("/customservice/kfsession/getsession", func(r *wechatmptest.Request) interface{} {
+		return &Session{CreateTime: 1600000000, KFAccount: "test@wechatmp"}
+	})
+	s.Handle("/customservice/kfsession/getwaitcase", func(r *wechatmptest.Request) interface{} {
+		return []byte(`{"count":1,"waitcaselist":[{"latest_time":1600000000,"openid":"openid"}]}`)
+	})
+	app := s.NewApp()
+	list, err := GetKFList(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].KFAccount != "test@wechatmp" || list[0].KFNick != "nick" || list[0].InviteStatus != "waiting" || list[0].InviteExpireTime != 1600000000 {
+		t.Fatal(list)
+	}
+	err = CreateSession(app, "test@wechatmp", "openid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := &paramsSession{}
+	err = s.Requests("/customservice/kfsession/create")[0].Unmarshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *params != (paramsSession{KFAccount: "test@wechatmp", OpenID: "openid"}) {
+		t.Fatal(params)
+	}
+	session, err := GetSession(app, "openid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.KFAccount != "test@wechatmp" || s.Requests("/customservice/kfsession/getsession")[0].Query.Get("openid") != "openid" {
+		t.Fatal(session)
+	}
+	waitcases, err := GetWaitCaseList(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if waitcases.Count != 1 || len(waitcases.WaitCaseList) != 1 || waitcases.WaitCaseList[0].OpenID != "openid" {
+		t.Fatal(waitcases)
+	}
+}
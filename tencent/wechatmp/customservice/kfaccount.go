@@ -0,0 +1,89 @@
+package customservice
+
+import (
+	"net/url"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//KFAccount kf account info
+type KFAccount struct {
+	KFAccount        string `json:"kf_account"`
+	KFHeadimgURL     string `json:"kf_headimgurl"`
+	KFID             string `json:"kf_id"`
+	KFNick           string `json:"kf_nick"`
+	KFWx             string `json:"kf_wx"`
+	InviteWx         string `json:"invite_wx"`
+	InviteExpireTime int64  `json:"invite_expire_time"`
+	InviteStatus     string `json:"invite_status"`
+}
+
+//OnlineKFAccount online kf account info
+type OnlineKFAccount struct {
+	KFAccount    string `json:"kf_account"`
+	Status       int    `json:"status"`
+	KFID         string `json:"kf_id"`
+	AcceptedCase int    `json:"accepted_case"`
+}
+
+type resultKFList struct {
+	KFList []*KFAccount `json:"kf_list"`
+}
+
+type resultKFOnlineList struct {
+	KFOnlineList []*OnlineKFAccount `json:"kf_online_list"`
+}
+
+type paramsKFAccount struct {
+	KFAccount string `json:"kf_account"`
+	Nickname  string `json:"nickname"`
+}
+
+//AddKFAccount add kf account with given account and nickname.
+//Account should be in format "name@wechatmp_id".
+func AddKFAccount(App *wechatmp.App, account string, nickname string) error {
+	params := &paramsKFAccount{
+		KFAccount: account,
+		Nickname:  nickname,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIKFAccountAdd, nil, params, result)
+}
+
+//UpdateKFAccount update nickname of kf account.
+func UpdateKFAccount(App *wechatmp.App, account string, nickname string) error {
+	params := &paramsKFAccount{
+		KFAccount: account,
+		Nickname:  nickname,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIKFAccountUpdate, nil, params, result)
+}
+
+//DeleteKFAccount delete kf account.
+func DeleteKFAccount(App *wechatmp.App, account string) error {
+	params := url.Values{}
+	params.Set("kf_account", account)
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIKFAccountDel, params, nil, result)
+}
+
+//GetKFList get all kf accounts.
+func GetKFList(App *wechatmp.App) ([]*KFAccount, error) {
+	result := &resultKFList{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIKFList, nil, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.KFList, nil
+}
+
+//GetOnlineKFList get online kf accounts.
+func GetOnlineKFList(App *wechatmp.App) ([]*OnlineKFAccount, error) {
+	result := &resultKFOnlineList{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIKFOnlineList, nil, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.KFOnlineList, nil
+}
@@ -0,0 +1,160 @@
+package customservice
+
+import "github.com/herb-go/providers/tencent/wechatmp"
+
+const MsgTypeText = "text"
+const MsgTypeImage = "image"
+const MsgTypeVoice = "voice"
+const MsgTypeVideo = "video"
+const MsgTypeMusic = "music"
+const MsgTypeNews = "news"
+const MsgTypeMPNews = "mpnews"
+const MsgTypeMenu = "msgmenu"
+const MsgTypeMiniprogramPage = "miniprogrampage"
+
+const TypingCommandTyping = "Typing"
+const TypingCommandCancelTyping = "CancelTyping"
+
+//Message customer service message
+type Message struct {
+	ToUser          string                  `json:"touser"`
+	MsgType         string                  `json:"msgtype"`
+	Text            *MessageText            `json:"text,omitempty"`
+	Image           *MessageMedia           `json:"image,omitempty"`
+	Voice           *MessageMedia           `json:"voice,omitempty"`
+	Video           *MessageVideo           `json:"video,omitempty"`
+	Music           *MessageMusic           `json:"music,omitempty"`
+	News            *MessageNews            `json:"news,omitempty"`
+	MPNews          *MessageMedia           `json:"mpnews,omitempty"`
+	Menu            *MessageMenu            `json:"msgmenu,omitempty"`
+	MiniprogramPage *MessageMiniprogramPage `json:"miniprogrampage,omitempty"`
+	CustomService   *MessageCustomService   `json:"customservice,omitempty"`
+}
+
+//SetMsgType set message type and create empty content of given type.
+func (m *Message) SetMsgType(MsgType string) {
+	m.MsgType = MsgType
+	switch MsgType {
+	case MsgTypeText:
+		m.Text = &MessageText{}
+	case MsgTypeImage:
+		m.Image = &MessageMedia{}
+	case MsgTypeVoice:
+		m.Voice = &MessageMedia{}
+	case MsgTypeVideo:
+		m.Video = &MessageVideo{}
+	case MsgTypeMusic:
+		m.Music = &MessageMusic{}
+	case MsgTypeNews:
+		m.News = &MessageNews{}
+	case MsgTypeMPNews:
+		m.MPNews = &MessageMedia{}
+	case MsgTypeMenu:
+		m.Menu = &MessageMenu{}
+	case MsgTypeMiniprogramPage:
+		m.MiniprogramPage = &MessageMiniprogramPage{}
+	}
+}
+
+//SetKFAccount send message as given kf account.
+func (m *Message) SetKFAccount(account string) {
+	m.CustomService = &MessageCustomService{
+		KFAccount: account,
+	}
+}
+
+type MessageText struct {
+	Content string `json:"content"`
+}
+
+type MessageMedia struct {
+	MediaID string `json:"media_id"`
+}
+
+type MessageVideo struct {
+	MediaID      string `json:"media_id"`
+	ThumbMediaID string `json:"thumb_media_id"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+}
+
+type MessageMusic struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	MusicURL     string `json:"musicurl"`
+	HQMusicURL   string `json:"hqmusicurl"`
+	ThumbMediaID string `json:"thumb_media_id"`
+}
+
+type MessageNews struct {
+	Articles []*MessageArticle `json:"articles"`
+}
+
+type MessageArticle struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	PicURL      string `json:"picurl"`
+}
+
+type MessageMenu struct {
+	HeadContent string             `json:"head_content"`
+	List        []*MessageMenuItem `json:"list"`
+	TailContent string             `json:"tail_content"`
+}
+
+type MessageMenuItem struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+}
+
+type MessageMiniprogramPage struct {
+	Title        string `json:"title"`
+	AppID        string `json:"appid"`
+	PagePath     string `json:"pagepath"`
+	ThumbMediaID string `json:"thumb_media_id"`
+}
+
+type MessageCustomService struct {
+	KFAccount string `json:"kf_account"`
+}
+
+//NewMessage create new message to given user with given type.
+func NewMessage(touser string, MsgType string) *Message {
+	m := &Message{
+		ToUser: touser,
+	}
+	m.SetMsgType(MsgType)
+	return m
+}
+
+//NewTextMessage create new text message to given user.
+func NewTextMessage(touser string, content string) *Message {
+	m := NewMessage(touser, MsgTypeText)
+	m.Text.Content = content
+	return m
+}
+
+//SendMessage send customer service message.
+func SendMessage(App *wechatmp.App, m *Message) error {
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIMessageCustomSend, nil, m, result)
+}
+
+type paramsTyping struct {
+	ToUser  string `json:"touser"`
+	Command string `json:"command"`
+}
+
+//SetTyping set typing status to given user.
+func SetTyping(App *wechatmp.App, touser string, typing bool) error {
+	params := &paramsTyping{
+		ToUser:  touser,
+		Command: TypingCommandCancelTyping,
+	}
+	if typing {
+		params.Command = TypingCommandTyping
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIMessageCustomTyping, nil, params, result)
+}
@@ -0,0 +1,94 @@
+package customservice
+
+import (
+	"net/url"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//Session kf session of user.
+type Session struct {
+	CreateTime int64  `json:"createtime"`
+	KFAccount  string `json:"kf_account"`
+}
+
+//SessionItem item of kf session list.
+type SessionItem struct {
+	CreateTime int64  `json:"createtime"`
+	OpenID     string `json:"openid"`
+}
+
+//WaitCase user waiting for kf session.
+type WaitCase struct {
+	LatestTime int64  `json:"latest_time"`
+	OpenID     string `json:"openid"`
+}
+
+//WaitCaseList waiting user list
+type WaitCaseList struct {
+	Count        int         `json:"count"`
+	WaitCaseList []*WaitCase `json:"waitcaselist"`
+}
+
+type resultSessionList struct {
+	SessionList []*SessionItem `json:"sessionlist"`
+}
+
+type paramsSession struct {
+	KFAccount string `json:"kf_account"`
+	OpenID    string `json:"openid"`
+}
+
+//CreateSession create kf session between kf account and user.
+func CreateSession(App *wechatmp.App, account string, openid string) error {
+	params := &paramsSession{
+		KFAccount: account,
+		OpenID:    openid,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIKFSessionCreate, nil, params, result)
+}
+
+//CloseSession close kf session between kf account and user.
+func CloseSession(App *wechatmp.App, account string, openid string) error {
+	params := &paramsSession{
+		KFAccount: account,
+		OpenID:    openid,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIKFSessionClose, nil, params, result)
+}
+
+//GetSession get kf session of user.
+func GetSession(App *wechatmp.App, openid string) (*Session, error) {
+	params := url.Values{}
+	params.Set("openid", openid)
+	result := &Session{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIKFSessionGet, params, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetSessionList get kf sessions of kf account.
+func GetSessionList(App *wechatmp.App, account string) ([]*SessionItem, error) {
+	params := url.Values{}
+	params.Set("kf_account", account)
+	result := &resultSessionList{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIKFSessionList, params, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.SessionList, nil
+}
+
+//GetWaitCaseList get users waiting for kf session.
+func GetWaitCaseList(App *wechatmp.App) (*WaitCaseList, error) {
+	result := &WaitCaseList{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIKFSessionWaitCase, nil, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
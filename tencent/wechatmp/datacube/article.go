@@ -0,0 +1,151 @@
+package datacube
+
+import (
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+const SpanArticleSummary = 1
+const SpanArticleTotal = 1
+const SpanUserRead = 3
+const SpanUserReadHour = 1
+const SpanUserShare = 7
+const SpanUserShareHour = 1
+
+//ArticleSummary article daily data
+type ArticleSummary struct {
+	RefDate          string `json:"ref_date"`
+	MsgID            string `json:"msgid"`
+	Title            string `json:"title"`
+	IntPageReadUser  int    `json:"int_page_read_user"`
+	IntPageReadCount int    `json:"int_page_read_count"`
+	OriPageReadUser  int    `json:"ori_page_read_user"`
+	OriPageReadCount int    `json:"ori_page_read_count"`
+	ShareUser        int    `json:"share_user"`
+	ShareCount       int    `json:"share_count"`
+	AddToFavUser     int    `json:"add_to_fav_user"`
+	AddToFavCount    int    `json:"add_to_fav_count"`
+}
+
+//ArticleTotalDetail article total data of stat date
+type ArticleTotalDetail struct {
+	StatDate                    string `json:"stat_date"`
+	TargetUser                  int    `json:"target_user"`
+	IntPageReadUser             int    `json:"int_page_read_user"`
+	IntPageReadCount            int    `json:"int_page_read_count"`
+	OriPageReadUser             int    `json:"ori_page_read_user"`
+	OriPageReadCount            int    `json:"ori_page_read_count"`
+	ShareUser                   int    `json:"share_user"`
+	ShareCount                  int    `json:"share_count"`
+	AddToFavUser                int    `json:"add_to_fav_user"`
+	AddToFavCount               int    `json:"add_to_fav_count"`
+	IntPageFromSessionReadUser  int    `json:"int_page_from_session_read_user"`
+	IntPageFromSessionReadCount int    `json:"int_page_from_session_read_count"`
+	IntPageFromHistMsgReadUser  int    `json:"int_page_from_hist_msg_read_user"`
+	IntPageFromHistMsgReadCount int    `json:"int_page_from_hist_msg_read_count"`
+	IntPageFromFeedReadUser     int    `json:"int_page_from_feed_read_user"`
+	IntPageFromFeedReadCount    int    `json:"int_page_from_feed_read_count"`
+	IntPageFromFriendsReadUser  int    `json:"int_page_from_friends_read_user"`
+	IntPageFromFriendsReadCount int    `json:"int_page_from_friends_read_count"`
+	IntPageFromOtherReadUser    int    `json:"int_page_from_other_read_user"`
+	IntPageFromOtherReadCount   int    `json:"int_page_from_other_read_count"`
+	FeedShareFromSessionUser    int    `json:"feed_share_from_session_user"`
+	FeedShareFromSessionCnt     int    `json:"feed_share_from_session_cnt"`
+	FeedShareFromFeedUser       int    `json:"feed_share_from_feed_user"`
+	FeedShareFromFeedCnt        int    `json:"feed_share_from_feed_cnt"`
+	FeedShareFromOtherUser      int    `json:"feed_share_from_other_user"`
+	FeedShareFromOtherCnt       int    `json:"feed_share_from_other_cnt"`
+}
+
+//ArticleTotal article total data
+type ArticleTotal struct {
+	RefDate string                `json:"ref_date"`
+	MsgID   string                `json:"msgid"`
+	Title   string                `json:"title"`
+	Details []*ArticleTotalDetail `json:"details"`
+}
+
+//UserRead article read data
+type UserRead struct {
+	RefDate          string `json:"ref_date"`
+	RefHour          int    `json:"ref_hour,omitempty"`
+	UserSource       int    `json:"user_source"`
+	IntPageReadUser  int    `json:"int_page_read_user"`
+	IntPageReadCount int    `json:"int_page_read_count"`
+	OriPageReadUser  int    `json:"ori_page_read_user"`
+	OriPageReadCount int    `json:"ori_page_read_count"`
+	ShareUser        int    `json:"share_user"`
+	ShareCount       int    `json:"share_count"`
+	AddToFavUser     int    `json:"add_to_fav_user"`
+	AddToFavCount    int    `json:"add_to_fav_count"`
+}
+
+//UserShare article share data
+type UserShare struct {
+	RefDate    string `json:"ref_date"`
+	RefHour    int    `json:"ref_hour,omitempty"`
+	ShareScene int    `json:"share_scene"`
+	ShareCount int    `json:"share_count"`
+	ShareUser  int    `json:"share_user"`
+}
+
+//GetArticleSummary get article daily data between begin and end date.
+func GetArticleSummary(App *wechatmp.App, begin time.Time, end time.Time) ([]*ArticleSummary, error) {
+	result := []*ArticleSummary{}
+	err := query(App, wechatmp.APIDataCubeGetArticleSummary, SpanArticleSummary, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetArticleTotal get article total data between begin and end date.
+func GetArticleTotal(App *wechatmp.App, begin time.Time, end time.Time) ([]*ArticleTotal, error) {
+	result := []*ArticleTotal{}
+	err := query(App, wechatmp.APIDataCubeGetArticleTotal, SpanArticleTotal, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetUserRead get article read data between begin and end date.
+func GetUserRead(App *wechatmp.App, begin time.Time, end time.Time) ([]*UserRead, error) {
+	result := []*UserRead{}
+	err := query(App, wechatmp.APIDataCubeGetUserRead, SpanUserRead, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetUserReadHour get hourly article read data between begin and end date.
+func GetUserReadHour(App *wechatmp.App, begin time.Time, end time.Time) ([]*UserRead, error) {
+	result := []*UserRead{}
+	err := query(App, wechatmp.APIDataCubeGetUserReadHour, SpanUserReadHour, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetUserShare get article share data between begin and end date.
+func GetUserShare(App *wechatmp.App, begin time.Time, end time.Time) ([]*UserShare, error) {
+	result := []*UserShare{}
+	err := query(App, wechatmp.APIDataCubeGetUserShare, SpanUserShare, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetUserShareHour get hourly article share data between begin and end date.
+func GetUserShareHour(App *wechatmp.App, begin time.Time, end time.Time) ([]*UserShare, error) {
+	result := []*UserShare{}
+	err := query(App, wechatmp.APIDataCubeGetUserShareHour, SpanUserShareHour, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
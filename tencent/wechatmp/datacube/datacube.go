@@ -0,0 +1,83 @@
+package datacube
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//DateFormat date format used by datacube api.
+const DateFormat = "2006-01-02"
+
+//ErrInvalidDateRange error raised when end date is before begin date.
+var ErrInvalidDateRange = errors.New("datacube: end date is before begin date")
+
+//DateRange inclusive date range.
+type DateRange struct {
+	Begin time.Time
+	End   time.Time
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+//SplitDateRange split inclusive date range into ranges with at most span days.
+func SplitDateRange(begin time.Time, end time.Time, span int) ([]*DateRange, error) {
+	begin = day(begin)
+	end = day(end)
+	if end.Before(begin) {
+		return nil, ErrInvalidDateRange
+	}
+	if span < 1 {
+		span = 1
+	}
+	result := []*DateRange{}
+	for current := begin; !current.After(end); current = current.AddDate(0, 0, span) {
+		last := current.AddDate(0, 0, span-1)
+		if last.After(end) {
+			last = end
+		}
+		result = append(result, &DateRange{Begin: current, End: last})
+	}
+	return result, nil
+}
+
+type paramsDateRange struct {
+	BeginDate string `json:"begin_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type resultList struct {
+	List []json.RawMessage `json:"list"`
+}
+
+//query call api with date ranges split by span,and unmarshal merged list into v.
+func query(App *wechatmp.App, api *fetcher.Preset, span int, begin time.Time, end time.Time, v interface{}) error {
+	ranges, err := SplitDateRange(begin, end, span)
+	if err != nil {
+		return err
+	}
+	items := []json.RawMessage{}
+	for _, r := range ranges {
+		params := &paramsDateRange{
+			BeginDate: r.Begin.Format(DateFormat),
+			EndDate:   r.End.Format(DateFormat),
+		}
+		result := &resultList{}
+		err = App.CallJSONApiWithAccessToken(api, nil, params, result)
+		if err != nil {
+			return err
+		}
+		items = append(items, result.List...)
+	}
+	bs, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, v)
+}
@@ -0,0 +1,119 @@
+package datacube
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/internal/wechatmptest"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse(DateFormat, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestSplitDateRange(t *testing.T) {
+	var tests = []struct {
+		begin  string
+		end    string
+		span   int
+		ranges []string
+	}{
+		{"2014-12-01", "2014-12-01", 7, []string{"2014-12-01", "2014-12-01"}},
+		{"2014-12-01", "2014-12-07", 7, []string{"2014-12-01", "2014-12-07"}},
+		{"2014-12-01", "2014-12-08", 7, []string{"2014-12-01", "2014-12-07", "2014-12-08", "2014-12-08"}},
+		{"2014-12-30", "2015-01-02", 1, []string{"2014-12-30", "2014-12-30", "2014-12-31", "2014-12-31", "2015-01-01", "2015-01-01", "2015-01-02", "2015-01-02"}},
+		{"2015-02-01", "2015-03-31", 30, []string{"2015-02-01", "2015-03-02", "2015-03-03", "2015-03-31"}},
+	}
+	for _, test := range tests {
+		ranges, err := SplitDateRange(date(test.begin), date(test.end), test.span)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ranges)*2 != len(test.ranges) {
+			t.Fatal(test, ranges)
+		}
+		for k, v := range ranges {
+			if v.Begin.Format(DateFormat) != test.ranges[k*2] || v.End.Format(DateFormat) != test.ranges[k*2+1] {
+				t.Fatal(test, k, v)
+			}
+		}
+	}
+	_, err := SplitDateRange(date("2014-12-02"), date("2014-12-01"), 7)
+	if err != ErrInvalidDateRange {
+		t.Fatal(err)
+	}
+	ranges, err := SplitDateRange(date("2014-12-01").Add(23*time.Hour), date("2014-12-02"), 1)
+	if err != nil || len(ranges) != 2 {
+		t.Fatal(ranges, err)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIDataCubeGetUserSummary, "POST", "/datacube/getusersummary")
+	s.Handle("/datacube/getusersummary", func(r *wechatmptest.Request) interface{} {
+		params := &paramsDateRange{}
+		err := r.Unmarshal(params)
+		if err != nil {
+			panic(err)
+		}
+		if params.BeginDate == "2014-12-15" {
+			return &wechatmp.ResultAPIError{Errcode: 61501, Errmsg: "date range error"}
+		}
+		list := []*UserSummary{}
+		for d := date(params.BeginDate); !d.After(date(params.EndDate)); d = d.AddDate(0, 0, 1) {
+			list = append(list, &UserSummary{RefDate: d.Format(DateFormat), NewUser: d.Day()})
+		}
+		return map[string]interface{}{"list": list}
+	})
+	app := s.NewApp()
+	result, err := GetUserSummary(app, date("2014-12-01"), date("2014-12-14"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 14 {
+		t.Fatal(len(result))
+	}
+	for k, v := range result {
+		d := date("2014-12-01").AddDate(0, 0, k)
+		if v.RefDate != d.Format(DateFormat) || v.NewUser != d.Day() {
+			t.Fatal(k, v)
+		}
+	}
+	ranges := []string{}
+	for _, r := range s.Requests("/datacube/getusersummary") {
+		params := &paramsDateRange{}
+		err = r.Unmarshal(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ranges = append(ranges, params.BeginDate, params.EndDate)
+	}
+	if !reflect.DeepEqual(ranges, []string{"2014-12-01", "2014-12-07", "2014-12-08", "2014-12-14"}) {
+		t.Fatal(ranges)
+	}
+	_, err = GetUserSummary(app, date("2014-12-08"), date("2014-12-28"))
+	if !fetcher.CompareAPIErrCode(err, 61501) {
+		t.Fatal(err)
+	}
+	requests := s.Requests("/datacube/getusersummary")
+	if len(requests) != 4 {
+		t.Fatal(len(requests))
+	}
+	params := &paramsDateRange{}
+	err = requests[3].Unmarshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *params != (paramsDateRange{BeginDate: "2014-12-15", EndDate: "2014-12-21"}) {
+		t.Fatal(params)
+	}
+}
@@ -0,0 +1,40 @@
+package datacube
+
+import (
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+const SpanInterfaceSummary = 30
+const SpanInterfaceSummaryHour = 1
+
+//InterfaceSummary interface call data
+type InterfaceSummary struct {
+	RefDate       string `json:"ref_date"`
+	RefHour       int    `json:"ref_hour,omitempty"`
+	CallbackCount int    `json:"callback_count"`
+	FailCount     int    `json:"fail_count"`
+	TotalTimeCost int    `json:"total_time_cost"`
+	MaxTimeCost   int    `json:"max_time_cost"`
+}
+
+//GetInterfaceSummary get daily interface call data between begin and end date.
+func GetInterfaceSummary(App *wechatmp.App, begin time.Time, end time.Time) ([]*InterfaceSummary, error) {
+	result := []*InterfaceSummary{}
+	err := query(App, wechatmp.APIDataCubeGetInterfaceSummary, SpanInterfaceSummary, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetInterfaceSummaryHour get hourly interface call data between begin and end date.
+func GetInterfaceSummaryHour(App *wechatmp.App, begin time.Time, end time.Time) ([]*InterfaceSummary, error) {
+	result := []*InterfaceSummary{}
+	err := query(App, wechatmp.APIDataCubeGetInterfaceSummaryHour, SpanInterfaceSummaryHour, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package datacube
+
+import (
+	"time"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+const SpanUpstreamMsg = 7
+const SpanUpstreamMsgHour = 1
+const SpanUpstreamMsgWeek = 30
+const SpanUpstreamMsgMonth = 30
+const SpanUpstreamMsgDist = 15
+const SpanUpstreamMsgDistWeek = 30
+const SpanUpstreamMsgDistMonth = 30
+
+//UpstreamMsg upstream message data
+type UpstreamMsg struct {
+	RefDate  string `json:"ref_date"`
+	RefHour  int    `json:"ref_hour,omitempty"`
+	MsgType  int    `json:"msg_type"`
+	MsgUser  int    `json:"msg_user"`
+	MsgCount int    `json:"msg_count"`
+}
+
+//UpstreamMsgDist upstream message distribution data
+type UpstreamMsgDist struct {
+	RefDate       string `json:"ref_date"`
+	CountInterval int    `json:"count_interval"`
+	MsgUser       int    `json:"msg_user"`
+}
+
+func getUpstreamMsg(App *wechatmp.App, api *fetcher.Preset, span int, begin time.Time, end time.Time) ([]*UpstreamMsg, error) {
+	result := []*UpstreamMsg{}
+	err := query(App, api, span, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func getUpstreamMsgDist(App *wechatmp.App, api *fetcher.Preset, span int, begin time.Time, end time.Time) ([]*UpstreamMsgDist, error) {
+	result := []*UpstreamMsgDist{}
+	err := query(App, api, span, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetUpstreamMsg get daily upstream message data between begin and end date.
+func GetUpstreamMsg(App *wechatmp.App, begin time.Time, end time.Time) ([]*UpstreamMsg, error) {
+	return getUpstreamMsg(App, wechatmp.APIDataCubeGetUpstreamMsg, SpanUpstreamMsg, begin, end)
+}
+
+//GetUpstreamMsgHour get hourly upstream message data between begin and end date.
+func GetUpstreamMsgHour(App *wechatmp.App, begin time.Time, end time.Time) ([]*UpstreamMsg, error) {
+	return getUpstreamMsg(App, wechatmp.APIDataCubeGetUpstreamMsgHour, SpanUpstreamMsgHour, begin, end)
+}
+
+//GetUpstreamMsgWeek get weekly upstream message data between begin and end date.
+func GetUpstreamMsgWeek(App *wechatmp.App, begin time.Time, end time.Time) ([]*UpstreamMsg, error) {
+	return getUpstreamMsg(App, wechatmp.APIDataCubeGetUpstreamMsgWeek, SpanUpstreamMsgWeek, begin, end)
+}
+
+//GetUpstreamMsgMonth get monthly upstream message data between begin and end date.
+func GetUpstreamMsgMonth(App *wechatmp.App, begin time.Time, end time.Time) ([]*UpstreamMsg, error) {
+	return getUpstreamMsg(App, wechatmp.APIDataCubeGetUpstreamMsgMonth, SpanUpstreamMsgMonth, begin, end)
+}
+
+//GetUpstreamMsgDist get daily upstream message distribution data between begin and end date.
+func GetUpstreamMsgDist(App *wechatmp.App, begin time.Time, end time.Time) ([]*UpstreamMsgDist, error) {
+	return getUpstreamMsgDist(App, wechatmp.APIDataCubeGetUpstreamMsgDist, SpanUpstreamMsgDist, begin, end)
+}
+
+//GetUpstreamMsgDistWeek get weekly upstream message distribution data between begin and end date.
+func GetUpstreamMsgDistWeek(App *wechatmp.App, begin time.Time, end time.Time) ([]*UpstreamMsgDist, error) {
+	return getUpstreamMsgDist(App, wechatmp.APIDataCubeGetUpstreamMsgDistWeek, SpanUpstreamMsgDistWeek, begin, end)
+}
+
+//GetUpstreamMsgDistMonth get monthly upstream message distribution data between begin and end date.
+func GetUpstreamMsgDistMonth(App *wechatmp.App, begin time.Time, end time.Time) ([]*UpstreamMsgDist, error) {
+	return getUpstreamMsgDist(App, wechatmp.APIDataCubeGetUpstreamMsgDistMonth, SpanUpstreamMsgDistMonth, begin, end)
+}
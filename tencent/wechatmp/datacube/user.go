@@ -0,0 +1,44 @@
+package datacube
+
+import (
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+const SpanUserSummary = 7
+const SpanUserCumulate = 7
+
+//UserSummary user increase and decrease data
+type UserSummary struct {
+	RefDate    string `json:"ref_date"`
+	UserSource int    `json:"user_source"`
+	NewUser    int    `json:"new_user"`
+	CancelUser int    `json:"cancel_user"`
+}
+
+//UserCumulate cumulate user data
+type UserCumulate struct {
+	RefDate      string `json:"ref_date"`
+	CumulateUser int    `json:"cumulate_user"`
+}
+
+//GetUserSummary get user summary between begin and end date.
+func GetUserSummary(App *wechatmp.App, begin time.Time, end time.Time) ([]*UserSummary, error) {
+	result := []*UserSummary{}
+	err := query(App, wechatmp.APIDataCubeGetUserSummary, SpanUserSummary, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetUserCumulate get cumulate user between begin and end date.
+func GetUserCumulate(App *wechatmp.App, begin time.Time, end time.Time) ([]*UserCumulate, error) {
+	result := []*UserCumulate{}
+	err := query(App, wechatmp.APIDataCubeGetUserCumulate, SpanUserCumulate, begin, end, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
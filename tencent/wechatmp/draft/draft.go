@@ -0,0 +1,157 @@
+package draft
+
+import (
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//Article draft article
+type Article struct {
+	Title              string `json:"title"`
+	Author             string `json:"author,omitempty"`
+	Digest             string `json:"digest,omitempty"`
+	Content            string `json:"content"`
+	ContentSourceURL   string `json:"content_source_url,omitempty"`
+	ThumbMediaID       string `json:"thumb_media_id"`
+	NeedOpenComment    int    `json:"need_open_comment"`
+	OnlyFansCanComment int    `json:"only_fans_can_comment"`
+	PicCrop2351        string `json:"pic_crop_235_1,omitempty"`
+	PicCrop11          string `json:"pic_crop_1_1,omitempty"`
+	//URL url of article,only returned by server.
+	URL string `json:"url,omitempty"`
+	//ThumbURL url of article thumb,only returned by server.
+	ThumbURL string `json:"thumb_url,omitempty"`
+	//IsDeleted whether article is deleted,only returned by published article.
+	IsDeleted bool `json:"is_deleted,omitempty"`
+}
+
+//News draft news content
+type News struct {
+	NewsItem   []*Article `json:"news_item"`
+	CreateTime int64      `json:"create_time,omitempty"`
+	UpdateTime int64      `json:"update_time,omitempty"`
+}
+
+//Item item of draft list.
+type Item struct {
+	MediaID    string `json:"media_id"`
+	Content    *News  `json:"content"`
+	UpdateTime int64  `json:"update_time"`
+}
+
+//List draft list
+type List struct {
+	TotalCount int     `json:"total_count"`
+	ItemCount  int     `json:"item_count"`
+	Item       []*Item `json:"item"`
+}
+
+//ResultAdd draft add result
+type ResultAdd struct {
+	MediaID string `json:"media_id"`
+}
+
+type resultCount struct {
+	TotalCount int `json:"total_count"`
+}
+
+type paramsAdd struct {
+	Articles []*Article `json:"articles"`
+}
+
+type paramsUpdate struct {
+	MediaID  string   `json:"media_id"`
+	Index    int      `json:"index"`
+	Articles *Article `json:"articles"`
+}
+
+type paramsMediaID struct {
+	MediaID string `json:"media_id"`
+}
+
+//ParamsBatchGet params used in batch get api
+type ParamsBatchGet struct {
+	Offset    int `json:"offset"`
+	Count     int `json:"count"`
+	NoContent int `json:"no_content"`
+}
+
+//NewParamsBatchGet create new batch get params.
+//Count should be between 1 and 20.
+//Content will not be returned if noContent is true.
+func NewParamsBatchGet(offset int, count int, noContent bool) *ParamsBatchGet {
+	p := &ParamsBatchGet{
+		Offset: offset,
+		Count:  count,
+	}
+	if noContent {
+		p.NoContent = 1
+	}
+	return p
+}
+
+//AddDraft add draft with given articles.
+func AddDraft(App *wechatmp.App, articles ...*Article) (*ResultAdd, error) {
+	params := &paramsAdd{
+		Articles: articles,
+	}
+	result := &ResultAdd{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIDraftAdd, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//UpdateDraft update article of draft.
+//Index starts from 0.
+func UpdateDraft(App *wechatmp.App, mediaID string, index int, article *Article) error {
+	params := &paramsUpdate{
+		MediaID:  mediaID,
+		Index:    index,
+		Articles: article,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIDraftUpdate, nil, params, result)
+}
+
+//GetDraft get draft by media id.
+func GetDraft(App *wechatmp.App, mediaID string) (*News, error) {
+	params := &paramsMediaID{
+		MediaID: mediaID,
+	}
+	result := &News{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIDraftGet, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//DeleteDraft delete draft by media id.
+func DeleteDraft(App *wechatmp.App, mediaID string) error {
+	params := &paramsMediaID{
+		MediaID: mediaID,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIDraftDelete, nil, params, result)
+}
+
+//BatchGetDraft get draft list.
+func BatchGetDraft(App *wechatmp.App, params *ParamsBatchGet) (*List, error) {
+	result := &List{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIDraftBatchGet, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//CountDraft get total count of drafts.
+func CountDraft(App *wechatmp.App) (int, error) {
+	result := &resultCount{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIDraftCount, nil, nil, result)
+	if err != nil {
+		return 0, err
+	}
+	return result.TotalCount, nil
+}
@@ -0,0 +1,167 @@
+package draft
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/internal/wechatmptest"
+)
+
+func newTestArticle(title string) *Article {
+	return &Article{
+		Title:              title,
+		Author:             "author",
+		Content:            "<p>content</p>",
+		ContentSourceURL:   "http://example.com/source",
+		ThumbMediaID:       "thumbmediaid",
+		NeedOpenComment:    1,
+		OnlyFansCanComment: 1,
+		PicCrop2351:        "0.1945_0_1_0.5236",
+	}
+}
+
+func TestDraft(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIDraftAdd, "POST", "/cgi-bin/draft/add")
+	s.Replace(t, &wechatmp.APIDraftUpdate, "POST", "/cgi-bin/draft/update")
+	s.Replace(t, &wechatmp.APIDraftGet, "POST", "/cgi-bin/draft/get")
+	s.Replace(t, &wechatmp.APIDraftDelete, "POST", "/cgi-bin/draft/delete")
+	s.Replace(t, &wechatmp.APIDraftBatchGet, "POST", "/cgi-bin/draft/batchget")
+	s.Replace(t, &wechatmp.APIDraftCount, "GET", "/cgi-bin/draft/count")
+	drafts := map[string][]*Article{}
+	s.Handle("/cgi-bin/draft/add", func(r *wechatmptest.Request) interface{} {
+		params := &paramsAdd{}
+		err := r.Unmarshal(params)
+		if err != nil {
+			panic(err)
+		}
+		drafts["mediaid"] = params.Articles
+		return &ResultAdd{MediaID: "mediaid"}
+	})
+	s.Handle("/cgi-bin/draft/update", func(r *wechatmptest.Request) interface{} {
+		params := &paramsUpdate{}
+		err := r.Unmarshal(params)
+		if err != nil {
+			panic(err)
+		}
+		articles := drafts[params.MediaID]
+		if params.Index >= len(articles) {
+			return &wechatmp.ResultAPIError{Errcode: 53405, Errmsg: "invalid index"}
+		}
+		articles[params.Index] = params.Articles
+		return &wechatmp.ResultAPIError{}
+	})
+	s.Handle("/cgi-bin/draft/get", func(r *wechatmptest.Request) interface{} {
+		params := &paramsMediaID{}
+		err := r.Unmarshal(params)
+		if err != nil {
+			panic(err)
+		}
+		for _, v := range drafts[params.MediaID] {
+			v.URL = "http://example.com/" + v.Title
+		}
+		return &News{NewsItem: drafts[params.MediaID], CreateTime: 1600000000, UpdateTime: 1600000001}
+	})
+	s.Handle("/cgi-bin/draft/delete", func(r *wechatmptest.Request) interface{} {
+		params := &paramsMediaID{}
+		err := r.Unmarshal(params)
+		if err != nil {
+			panic(err)
+		}
+		delete(drafts, params.MediaID)
+		return &wechatmp.ResultAPIError{}
+	})
+	s.Handle("/cgi-bin/draft/batchget", func(r *wechatmptest.Request) interface{} {
+		list := &List{TotalCount: len(drafts), Item: []*Item{}}
+		for k, v := range drafts {
+			list.Item = append(list.Item, &Item{MediaID: k, Content: &News{NewsItem: v}, UpdateTime: 1600000001})
+		}
+		list.ItemCount = len(list.Item)
+		return list
+	})
+	s.Handle("/cgi-bin/draft/count", func(r *wechatmptest.Request) interface{} {
+		return &resultCount{TotalCount: len(drafts)}
+	})
+	app := s.NewApp()
+	result, err := AddDraft(app, newTestArticle("article1"), newTestArticle("article2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MediaID != "mediaid" {
+		t.Fatal(result)
+	}
+	added := &paramsAdd{}
+	err = s.Requests("/cgi-bin/draft/add")[0].Unmarshal(added)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(added.Articles, []*Article{newTestArticle("article1"), newTestArticle("article2")}) {
+		t.Fatal(added.Articles)
+	}
+	err = UpdateDraft(app, "mediaid", 1, newTestArticle("updated"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := &paramsUpdate{}
+	err = s.Requests("/cgi-bin/draft/update")[0].Unmarshal(updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.MediaID != "mediaid" || updated.Index != 1 || !reflect.DeepEqual(updated.Articles, newTestArticle("updated")) {
+		t.Fatal(updated)
+	}
+	news, err := GetDraft(app, "mediaid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(news.NewsItem) != 2 || news.CreateTime != 1600000000 || news.UpdateTime != 1600000001 {
+		t.Fatal(news)
+	}
+	article := newTestArticle("updated")
+	article.URL = "http://example.com/updated"
+	if !reflect.DeepEqual(news.NewsItem[1], article) || news.NewsItem[0].Title != "article1" {
+		t.Fatal(news.NewsItem[1])
+	}
+	count, err := CountDraft(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatal(count)
+	}
+	list, err := BatchGetDraft(app, NewParamsBatchGet(0, 20, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.TotalCount != 1 || list.ItemCount != 1 || list.Item[0].MediaID != "mediaid" || len(list.Item[0].Content.NewsItem) != 2 || list.Item[0].Content.NewsItem[1].Title != "updated" {
+		t.Fatal(list)
+	}
+	batchget := &ParamsBatchGet{}
+	err = s.Requests("/cgi-bin/draft/batchget")[0].Unmarshal(batchget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *batchget != (ParamsBatchGet{Offset: 0, Count: 20, NoContent: 0}) {
+		t.Fatal(batchget)
+	}
+	err = DeleteDraft(app, "mediaid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err = CountDraft(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatal(count)
+	}
+}
+
+func TestNewParamsBatchGet(t *testing.T) {
+	p := NewParamsBatchGet(20, 10, true)
+	if *p != (ParamsBatchGet{Offset: 20, Count: 10, NoContent: 1}) {
+		t.Fatal(p)
+	}
+}
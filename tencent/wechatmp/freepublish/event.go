@@ -0,0 +1,26 @@
+package freepublish
+
+import "encoding/xml"
+
+//EventPublishJobFinish event sent when publish job finished.
+const EventPublishJobFinish = "PUBLISHJOBFINISH"
+
+//PublishJobFinishEvent publish job finish event
+type PublishJobFinishEvent struct {
+	ToUserName       string
+	FromUserName     string
+	CreateTime       int64
+	MsgType          string
+	Event            string
+	PublishEventInfo *Status
+}
+
+//ParsePublishJobFinishEvent parse publish job finish event from receiver message content.
+func ParsePublishJobFinishEvent(content []byte) (*PublishJobFinishEvent, error) {
+	e := &PublishJobFinishEvent{}
+	err := xml.Unmarshal(content, e)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,55 @@
+package freepublish
+
+import "testing"
+
+func TestParsePublishJobFinishEvent(t *testing.T) {
+	content := []byte(`<xml>
+<ToUserName><![CDATA[gh_4d00ed8d6399]]></ToUserName>
+<FromUserName><![CDATA[oV5CrjpxgaGXNHIQigzNlgLTnwic]]></FromUserName>
+<CreateTime>1481013459</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[PUBLISHJOBFINISH]]></Event>
+<PublishEventInfo>
+<publish_id>2247503051</publish_id>
+<publish_status>0</publish_status>
+<article_id><![CDATA[b5O2OUs25HBxRceL7hfReg-U9QGeq9zQjiDvy]]></article_id>
+<article_detail>
+<count>1</count>
+<item>
+<idx>1</idx>
+<article_url><![CDATA[ARTICLE_URL]]></article_url>
+</item>
+</article_detail>
+</PublishEventInfo>
+</xml>`)
+	e, err := ParsePublishJobFinishEvent(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Event != EventPublishJobFinish || e.PublishEventInfo == nil {
+		t.Fatal(e)
+	}
+	info := e.PublishEventInfo
+	if info.PublishID != "2247503051" || info.PublishStatus != PublishStatusSuccess || info.ArticleID != "b5O2OUs25HBxRceL7hfReg-U9QGeq9zQjiDvy" {
+		t.Fatal(info)
+	}
+	if info.ArticleDetail.Count != 1 || len(info.ArticleDetail.Item) != 1 || info.ArticleDetail.Item[0].ArticleURL != "ARTICLE_URL" {
+		t.Fatal(info.ArticleDetail)
+	}
+	content = []byte(`<xml>
+<Event><![CDATA[PUBLISHJOBFINISH]]></Event>
+<PublishEventInfo>
+<publish_id>2247503051</publish_id>
+<publish_status>2</publish_status>
+<fail_idx>1</fail_idx>
+<fail_idx>2</fail_idx>
+</PublishEventInfo>
+</xml>`)
+	e, err = ParsePublishJobFinishEvent(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.PublishEventInfo.PublishStatus != PublishStatusOriginalFail || len(e.PublishEventInfo.FailIdx) != 2 || e.PublishEventInfo.FailIdx[1] != 2 {
+		t.Fatal(e.PublishEventInfo)
+	}
+}
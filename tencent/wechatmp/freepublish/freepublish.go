@@ -0,0 +1,133 @@
+package freepublish
+
+import (
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/draft"
+)
+
+const PublishStatusSuccess = 0
+const PublishStatusPublishing = 1
+const PublishStatusOriginalFail = 2
+const PublishStatusFail = 3
+const PublishStatusAuditFail = 4
+const PublishStatusDeleted = 5
+const PublishStatusBanned = 6
+
+//ResultSubmit publish submit result
+type ResultSubmit struct {
+	PublishID string `json:"publish_id"`
+	MsgDataID int64  `json:"msg_data_id"`
+}
+
+//ArticleDetailItem url of published article.
+type ArticleDetailItem struct {
+	Idx        int    `json:"idx" xml:"idx"`
+	ArticleURL string `json:"article_url" xml:"article_url"`
+}
+
+//ArticleDetail urls of published articles.
+type ArticleDetail struct {
+	Count int                  `json:"count" xml:"count"`
+	Item  []*ArticleDetailItem `json:"item" xml:"item"`
+}
+
+//Status publish status
+type Status struct {
+	PublishID     string         `json:"publish_id" xml:"publish_id"`
+	PublishStatus int            `json:"publish_status" xml:"publish_status"`
+	ArticleID     string         `json:"article_id" xml:"article_id"`
+	ArticleDetail *ArticleDetail `json:"article_detail" xml:"article_detail"`
+	//FailIdx indexes of failed articles,starts from 1.
+	FailIdx []int `json:"fail_idx" xml:"fail_idx"`
+}
+
+//Item item of published list.
+type Item struct {
+	ArticleID  string      `json:"article_id"`
+	Content    *draft.News `json:"content"`
+	UpdateTime int64       `json:"update_time"`
+}
+
+//List published list
+type List struct {
+	TotalCount int     `json:"total_count"`
+	ItemCount  int     `json:"item_count"`
+	Item       []*Item `json:"item"`
+}
+
+type paramsMediaID struct {
+	MediaID string `json:"media_id"`
+}
+
+type paramsPublishID struct {
+	PublishID string `json:"publish_id"`
+}
+
+type paramsArticleID struct {
+	ArticleID string `json:"article_id"`
+}
+
+type paramsDelete struct {
+	ArticleID string `json:"article_id"`
+	Index     int    `json:"index,omitempty"`
+}
+
+//Submit submit draft with given media id to publish.
+func Submit(App *wechatmp.App, mediaID string) (*ResultSubmit, error) {
+	params := &paramsMediaID{
+		MediaID: mediaID,
+	}
+	result := &ResultSubmit{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIFreePublishSubmit, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetStatus get publish status by publish id.
+func GetStatus(App *wechatmp.App, publishID string) (*Status, error) {
+	params := &paramsPublishID{
+		PublishID: publishID,
+	}
+	result := &Status{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIFreePublishGet, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//Delete delete published article.
+//Index starts from 1,and all articles will be deleted if index is 0.
+func Delete(App *wechatmp.App, articleID string, index int) error {
+	params := &paramsDelete{
+		ArticleID: articleID,
+		Index:     index,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIFreePublishDelete, nil, params, result)
+}
+
+//GetArticle get published article by article id.
+func GetArticle(App *wechatmp.App, articleID string) (*draft.News, error) {
+	params := &paramsArticleID{
+		ArticleID: articleID,
+	}
+	result := &draft.News{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIFreePublishGetArticle, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//BatchGet get published list.
+func BatchGet(App *wechatmp.App, params *draft.ParamsBatchGet) (*List, error) {
+	result := &List{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIFreePublishBatchGet, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
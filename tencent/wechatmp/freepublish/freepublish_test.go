@@ -0,0 +1,133 @@
+package freepublish
+
+import (
+	"testing"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/draft"
+	"github.com/herb-go/providers/tencent/wechatmp/internal/wechatmptest"
+)
+
+func TestSubmit(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIFreePublishSubmit, "POST", "/cgi-bin/freepublish/submit")
+	s.Handle("/cgi-bin/freepublish/submit", func(r *wechatmptest.Request) interface{} {
+		return []byte(`{"errcode":0,"errmsg":"ok","publish_id":"100000001","msg_data_id":2247483795}`)
+	})
+	result, err := Submit(s.NewApp(), "mediaid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PublishID != "100000001" || result.MsgDataID != 2247483795 {
+		t.Fatal(result)
+	}
+	params := &paramsMediaID{}
+	err = s.Requests("/cgi-bin/freepublish/submit")[0].Unmarshal(params)
+	if err != nil || params.MediaID != "mediaid" {
+		t.Fatal(params, err)
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIFreePublishGet, "POST", "/cgi-bin/freepublish/get")
+	s.Handle("/cgi-bin/freepublish/get", func(r *wechatmptest.Request) interface{} {
+		params := &paramsPublishID{}
+		err := r.Unmarshal(params)
+		if err != nil {
+			panic(err)
+		}
+		if params.PublishID == "failed" {
+			return []byte(`{"publish_id":"failed","publish_status":2,"fail_idx":[1,2]}`)
+		}
+		return []byte(`{"publish_id":"100000001","publish_status":0,"article_id":"articleid","article_detail":{"count":1,"item":[{"idx":1,"article_url":"http://example.com/article"}]},"fail_idx":[]}`)
+	})
+	app := s.NewApp()
+	status, err := GetStatus(app, "100000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.PublishID != "100000001" || status.PublishStatus != PublishStatusSuccess || status.ArticleID != "articleid" {
+		t.Fatal(status)
+	}
+	if status.ArticleDetail.Count != 1 || status.ArticleDetail.Item[0].Idx != 1 || status.ArticleDetail.Item[0].ArticleURL != "http://example.com/article" {
+		t.Fatal(status.ArticleDetail)
+	}
+	status, err = GetStatus(app, "failed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.PublishStatus != PublishStatusOriginalFail || len(status.FailIdx) != 2 || status.FailIdx[1] != 2 {
+		t.Fatal(status)
+	}
+}
+
+func TestArticles(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIFreePublishGetArticle, "POST", "/cgi-bin/freepublish/getarticle")
+	s.Replace(t, &wechatmp.APIFreePublishBatchGet, "POST", "/cgi-bin/freepublish/batchget")
+	s.Replace(t, &wechatmp.APIFreePublishDelete, "POST", "/cgi-bin/freepublish/delete")
+	newsItem := `{"news_item":[{"title":"title","author":"author","content":"content","thumb_media_id":"thumb","need_open_comment":1,"only_fans_can_comment":0,"url":"http://example.com/article","is_deleted":true}]`
+	s.Handle("/cgi-bin/freepublish/getarticle", func(r *wechatmptest.Request) interface{} {
+		return []byte(newsItem + `}`)
+	})
+	s.Handle("/cgi-bin/freepublish/batchget", func(r *wechatmptest.Request) interface{} {
+		return []byte(`{"total_count":3,"item_count":1,"item":[{"article_id":"articleid","content":` + newsItem + `,"create_time":1600000000,"update_time":1600000001},"update_time":1600000001}]}`)
+	})
+	s.Handle("/cgi-bin/freepublish/delete", func(r *wechatmptest.Request) interface{} {
+		return &wechatmp.ResultAPIError{}
+	})
+	app := s.NewApp()
+	news, err := GetArticle(app, "articleid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(news.NewsItem) != 1 || news.NewsItem[0].Title != "title" || news.NewsItem[0].NeedOpenComment != 1 || !news.NewsItem[0].IsDeleted || news.NewsItem[0].URL != "http://example.com/article" {
+		t.Fatal(news)
+	}
+	params := &paramsArticleID{}
+	err = s.Requests("/cgi-bin/freepublish/getarticle")[0].Unmarshal(params)
+	if err != nil || params.ArticleID != "articleid" {
+		t.Fatal(params, err)
+	}
+	list, err := BatchGet(app, draft.NewParamsBatchGet(2, 1, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.TotalCount != 3 || list.ItemCount != 1 || list.Item[0].ArticleID != "articleid" || list.Item[0].UpdateTime != 1600000001 {
+		t.Fatal(list)
+	}
+	if list.Item[0].Content.CreateTime != 1600000000 || list.Item[0].Content.NewsItem[0].ThumbMediaID != "thumb" {
+		t.Fatal(list.Item[0].Content)
+	}
+	batchget := &draft.ParamsBatchGet{}
+	err = s.Requests("/cgi-bin/freepublish/batchget")[0].Unmarshal(batchget)
+	if err != nil || *batchget != (draft.ParamsBatchGet{Offset: 2, Count: 1}) {
+		t.Fatal(batchget, err)
+	}
+	err = Delete(app, "articleid", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Delete(app, "articleid", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := s.Requests("/cgi-bin/freepublish/delete")
+	if len(requests) != 2 {
+		t.Fatal(requests)
+	}
+	deleteAll := map[string]interface{}{}
+	err = requests[0].Unmarshal(&deleteAll)
+	if err != nil || len(deleteAll) != 1 || deleteAll["article_id"] != "articleid" {
+		t.Fatal(deleteAll, err)
+	}
+	deleteOne := &paramsDelete{}
+	err = requests[1].Unmarshal(deleteOne)
+	if err != nil || *deleteOne != (paramsDelete{ArticleID: "articleid", Index: 2}) {
+		t.Fatal(deleteOne, err)
+	}
+}
@@ -0,0 +1,127 @@
+//Package wechatmptest provides fake wechat mp api server for tests of wechatmp packages.
+package wechatmptest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//AccessToken access token accepted by fake server.
+const AccessToken = "token"
+
+//Request request received by fake server.
+type Request struct {
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+//Unmarshal unmarshal json request body to v.
+func (r *Request) Unmarshal(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+//HandlerFunc func which returns response of given request.
+//Response will be written directly if it is []byte,or marshaled as json otherwise.
+type HandlerFunc func(r *Request) interface{}
+
+//Server fake wechat mp api server.
+type Server struct {
+	*httptest.Server
+	lock     sync.Mutex
+	handlers map[string]HandlerFunc
+	requests []*Request
+}
+
+//NewServer create and start new fake server.
+func NewServer() *Server {
+	s := &Server{
+		handlers: map[string]HandlerFunc{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+//Handle register handler for given path.
+func (s *Server) Handle(path string, h HandlerFunc) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers[path] = h
+}
+
+//Requests return requests received with given path.
+func (s *Server) Requests(path string) []*Request {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	result := []*Request{}
+	for _, v := range s.requests {
+		if v.Path == path {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		panic(err)
+	}
+	req := &Request{
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header,
+		Body:   body,
+	}
+	s.lock.Lock()
+	s.requests = append(s.requests, req)
+	h := s.handlers[r.URL.Path]
+	s.lock.Unlock()
+	if h == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if req.Query.Get("access_token") != AccessToken {
+		w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+		return
+	}
+	result := h(req)
+	data, ok := result.([]byte)
+	if !ok {
+		data, err = json.Marshal(result)
+		if err != nil {
+			panic(err)
+		}
+	}
+	w.Write(data)
+}
+
+//Replace replace given api with endpoint of fake server until test finished.
+func (s *Server) Replace(t testing.TB, api **fetcher.Preset, method string, path string) {
+	old := *api
+	*api = fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint(method, path)
+	t.Cleanup(func() {
+		*api = old
+	})
+}
+
+//NewApp create app whose access token is accepted by fake server.
+func (s *Server) NewApp() *wechatmp.App {
+	app := &wechatmp.App{
+		AppID:     "appid",
+		AppSecret: "secret",
+	}
+	app.SetAccessTokenRefresher(func(string) (string, error) {
+		return AccessToken, nil
+	})
+	return app
+}
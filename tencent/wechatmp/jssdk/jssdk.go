@@ -0,0 +1,74 @@
+package jssdk
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//NonceLength length of generated nonce str.
+var NonceLength = 16
+
+const nonceChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+//Config js-sdk wx.config params.
+type Config struct {
+	AppID     string `json:"appId"`
+	Timestamp int64  `json:"timestamp"`
+	NonceStr  string `json:"nonceStr"`
+	Signature string `json:"signature"`
+}
+
+//NewNonceStr create random nonce str.
+func NewNonceStr() (string, error) {
+	buf := make([]byte, NonceLength)
+	max := big.NewInt(int64(len(nonceChars)))
+	for k := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[k] = nonceChars[n.Int64()]
+	}
+	return string(buf), nil
+}
+
+//Sign create wx.config signature with given jsapi ticket,nonce str,timestamp and page url.
+//Fragment of page url will be removed.
+func Sign(ticket string, noncestr string, timestamp int64, pageURL string) string {
+	if i := strings.Index(pageURL, "#"); i >= 0 {
+		pageURL = pageURL[:i]
+	}
+	data := "jsapi_ticket=" + ticket +
+		"&noncestr=" + noncestr +
+		"&timestamp=" + strconv.FormatInt(timestamp, 10) +
+		"&url=" + pageURL
+	h := sha1.New()
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+//NewConfig create wx.config params for given page url with jsapi ticket of app.
+func NewConfig(App *wechatmp.App, pageURL string) (*Config, error) {
+	ticket, err := App.JSAPITicket()
+	if err != nil {
+		return nil, err
+	}
+	noncestr, err := NewNonceStr()
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{
+		AppID:     App.AppID,
+		Timestamp: time.Now().Unix(),
+		NonceStr:  noncestr,
+	}
+	c.Signature = Sign(ticket, c.NonceStr, c.Timestamp, pageURL)
+	return c, nil
+}
@@ -0,0 +1,26 @@
+package jssdk
+
+import "testing"
+
+func TestSign(t *testing.T) {
+	ticket := "sM4AOVdWfPE4DxkXGEs8VMCPGGVi4C3VM0P37wVUCFvkVAy_90u5h9nbSlYy3-Sl-HhTdfl2fzFy1AOcHKP7qg"
+	wanted := "0f9de62fce790f9a083d5c99e95740ceb90c27ed"
+	s := Sign(ticket, "Wm3WZYTPz0wzccnW", 1414587457, "http://mp.weixin.qq.com?params=value")
+	if s != wanted {
+		t.Fatal(s)
+	}
+	s = Sign(ticket, "Wm3WZYTPz0wzccnW", 1414587457, "http://mp.weixin.qq.com?params=value#fragment")
+	if s != wanted {
+		t.Fatal(s)
+	}
+}
+
+func TestNonceStr(t *testing.T) {
+	s, err := NewNonceStr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != NonceLength {
+		t.Fatal(s)
+	}
+}
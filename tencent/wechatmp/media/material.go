@@ -0,0 +1,171 @@
+package media
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//VideoDescription description of permanent video material.
+type VideoDescription struct {
+	Title        string `json:"title"`
+	Introduction string `json:"introduction"`
+}
+
+//ResultAddMaterial permanent material add result
+type ResultAddMaterial struct {
+	MediaID string `json:"media_id"`
+	URL     string `json:"url"`
+}
+
+//NewsItem article of news material.
+type NewsItem struct {
+	Title              string `json:"title"`
+	ThumbMediaID       string `json:"thumb_media_id"`
+	ShowCoverPic       int    `json:"show_cover_pic"`
+	Author             string `json:"author"`
+	Digest             string `json:"digest"`
+	Content            string `json:"content"`
+	URL                string `json:"url"`
+	ContentSourceURL   string `json:"content_source_url"`
+	NeedOpenComment    int    `json:"need_open_comment"`
+	OnlyFansCanComment int    `json:"only_fans_can_comment"`
+}
+
+//Material downloaded permanent material.
+type Material struct {
+	Media
+	//Title title of video material.
+	Title string `json:"title"`
+	//Description description of video material.
+	Description string `json:"description"`
+	//DownURL download url of video material.
+	DownURL string `json:"down_url"`
+	//NewsItem articles of news material.
+	NewsItem []*NewsItem `json:"news_item"`
+}
+
+//MaterialCount permanent material count
+type MaterialCount struct {
+	VoiceCount int `json:"voice_count"`
+	VideoCount int `json:"video_count"`
+	ImageCount int `json:"image_count"`
+	NewsCount  int `json:"news_count"`
+}
+
+//MaterialContent content of news material item.
+type MaterialContent struct {
+	NewsItem   []*NewsItem `json:"news_item"`
+	CreateTime int64       `json:"create_time"`
+	UpdateTime int64       `json:"update_time"`
+}
+
+//MaterialItem item of material list.
+type MaterialItem struct {
+	MediaID    string           `json:"media_id"`
+	Name       string           `json:"name"`
+	UpdateTime int64            `json:"update_time"`
+	URL        string           `json:"url"`
+	Content    *MaterialContent `json:"content"`
+}
+
+//MaterialList permanent material list
+type MaterialList struct {
+	TotalCount int             `json:"total_count"`
+	ItemCount  int             `json:"item_count"`
+	Item       []*MaterialItem `json:"item"`
+}
+
+type paramsMediaID struct {
+	MediaID string `json:"media_id"`
+}
+
+type paramsBatchGet struct {
+	Type   MediaType `json:"type"`
+	Offset int       `json:"offset"`
+	Count  int       `json:"count"`
+}
+
+//AddMaterial upload permanent material.
+//Description is required when uploading video material,and ignored otherwise.
+func AddMaterial(App *wechatmp.App, mediatype MediaType, filename string, body io.Reader, description *VideoDescription) (*ResultAddMaterial, error) {
+	params := url.Values{}
+	params.Set("type", string(mediatype))
+	var form url.Values
+	if mediatype == MediaTypeVideo && description != nil {
+		bs, err := json.Marshal(description)
+		if err != nil {
+			return nil, err
+		}
+		form = url.Values{}
+		form.Set("description", string(bs))
+	}
+	result := &ResultAddMaterial{}
+	err := App.UploadApiWithAccessToken(wechatmp.APIMaterialAdd, params, form, filename, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetMaterial download permanent material by media id.
+//Body will be set for image,voice and thumb material,
+//while json fields will be set for video and news material.
+func GetMaterial(App *wechatmp.App, mediaID string) (*Material, error) {
+	params := &paramsMediaID{
+		MediaID: mediaID,
+	}
+	resp, err := App.DownloadApiWithAccessToken(wechatmp.APIMaterialGet, nil, params)
+	if err != nil {
+		return nil, err
+	}
+	m := &Material{
+		Media: *newMedia(resp),
+	}
+	if isJSON(&m.Media) {
+		body := m.Body
+		m.Body = nil
+		err = json.Unmarshal(body, m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+//DeleteMaterial delete permanent material by media id.
+func DeleteMaterial(App *wechatmp.App, mediaID string) error {
+	params := &paramsMediaID{
+		MediaID: mediaID,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIMaterialDel, nil, params, result)
+}
+
+//GetMaterialCount get permanent material count.
+func GetMaterialCount(App *wechatmp.App) (*MaterialCount, error) {
+	result := &MaterialCount{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIMaterialGetCount, nil, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//BatchGetMaterial get permanent material list of given type.
+//Count should be between 1 and 20.
+func BatchGetMaterial(App *wechatmp.App, mediatype MediaType, offset int, count int) (*MaterialList, error) {
+	params := &paramsBatchGet{
+		Type:   mediatype,
+		Offset: offset,
+		Count:  count,
+	}
+	result := &MaterialList{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIMaterialBatchGet, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,111 @@
+package media
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/url"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//MediaType wechat mp media type
+type MediaType string
+
+const MediaTypeImage = MediaType("image")
+const MediaTypeVoice = MediaType("voice")
+const MediaTypeVideo = MediaType("video")
+const MediaTypeThumb = MediaType("thumb")
+const MediaTypeNews = MediaType("news")
+
+//ResultMediaUpload temporary media upload result
+type ResultMediaUpload struct {
+	Type      string `json:"type"`
+	MediaID   string `json:"media_id"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+//Media downloaded media
+type Media struct {
+	//ContentType content type of media.
+	ContentType string
+	//Filename filename in response content disposition.
+	Filename string
+	//Body media content.
+	Body []byte
+	//VideoURL download url of video media.
+	//Body will be empty if VideoURL is returned.
+	VideoURL string
+}
+
+type resultVideoURL struct {
+	VideoURL string `json:"video_url"`
+}
+
+//UploadMedia upload temporary media.
+func UploadMedia(App *wechatmp.App, mediatype MediaType, filename string, body io.Reader) (*ResultMediaUpload, error) {
+	params := url.Values{}
+	params.Set("type", string(mediatype))
+	result := &ResultMediaUpload{}
+	err := App.UploadApiWithAccessToken(wechatmp.APIMediaUpload, params, nil, filename, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func newMedia(resp *fetcher.Response) *Media {
+	m := &Media{
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        resp.BodyContent,
+	}
+	_, dispositionParams, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition"))
+	if err == nil {
+		m.Filename = dispositionParams["filename"]
+	}
+	return m
+}
+
+//isJSON check if media body is json instead of media content.
+func isJSON(m *Media) bool {
+	body := bytes.TrimSpace(m.Body)
+	return len(body) > 0 && body[0] == '{'
+}
+
+//GetMedia download temporary media by media id.
+func GetMedia(App *wechatmp.App, mediaID string) (*Media, error) {
+	params := url.Values{}
+	params.Set("media_id", mediaID)
+	resp, err := App.DownloadApiWithAccessToken(wechatmp.APIMediaGet, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	m := newMedia(resp)
+	if isJSON(m) {
+		result := &resultVideoURL{}
+		err = json.Unmarshal(m.Body, result)
+		if err != nil {
+			return nil, err
+		}
+		m.VideoURL = result.VideoURL
+		m.Body = nil
+	}
+	return m, nil
+}
+
+type resultUploadImg struct {
+	URL string `json:"url"`
+}
+
+//UploadImage upload image used in article content.
+//Image url will be returned.
+func UploadImage(App *wechatmp.App, filename string, body io.Reader) (string, error) {
+	result := &resultUploadImg{}
+	err := App.UploadApiWithAccessToken(wechatmp.APIMediaUploadImg, nil, nil, filename, body, result)
+	if err != nil {
+		return "", err
+	}
+	return result.URL, nil
+}
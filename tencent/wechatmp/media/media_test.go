@@ -0,0 +1,192 @@
+package media
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"testing"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/internal/wechatmptest"
+)
+
+type uploaded struct {
+	Filename string
+	Content  []byte
+	Form     map[string]string
+}
+
+func parseUpload(r *wechatmptest.Request) *uploaded {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		panic(err)
+	}
+	result := &uploaded{Form: map[string]string{}}
+	mr := multipart.NewReader(bytes.NewReader(r.Body), params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return result
+		}
+		if err != nil {
+			panic(err)
+		}
+		data, err := ioutil.ReadAll(part)
+		if err != nil {
+			panic(err)
+		}
+		if part.FormName() == "media" {
+			result.Filename = part.FileName()
+			result.Content = data
+			continue
+		}
+		result.Form[part.FormName()] = string(data)
+	}
+}
+
+//newRejectedApp create app whose first access token is rejected by fake server.
+func newRejectedApp(s *wechatmptest.Server) *wechatmp.App {
+	app := s.NewApp()
+	granted := false
+	app.SetAccessTokenRefresher(func(old string) (string, error) {
+		if !granted {
+			granted = true
+			return "rejected", nil
+		}
+		return wechatmptest.AccessToken, nil
+	})
+	return app
+}
+
+func TestUploadMedia(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIMediaUpload, "POST", "/cgi-bin/media/upload")
+	s.Handle("/cgi-bin/media/upload", func(r *wechatmptest.Request) interface{} {
+		return &ResultMediaUpload{Type: r.Query.Get("type"), MediaID: "mediaid", CreatedAt: 1600000000}
+	})
+	content := bytes.Repeat([]byte("content"), 100000)
+	body := bytes.NewReader(content)
+	body.Seek(7, io.SeekStart)
+	result, err := UploadMedia(newRejectedApp(s), MediaTypeImage, "image.jpg", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (ResultMediaUpload{Type: "image", MediaID: "mediaid", CreatedAt: 1600000000}) {
+		t.Fatal(result)
+	}
+	requests := s.Requests("/cgi-bin/media/upload")
+	if len(requests) != 2 || requests[0].Query.Get("access_token") != "rejected" || requests[1].Query.Get("access_token") != wechatmptest.AccessToken {
+		t.Fatal(requests)
+	}
+	for _, r := range requests {
+		u := parseUpload(r)
+		if u.Filename != "image.jpg" || !bytes.Equal(u.Content, content[7:]) {
+			t.Fatal(u.Filename, len(u.Content))
+		}
+	}
+	_, err = UploadMedia(newRejectedApp(s), MediaTypeImage, "image.jpg", bytes.NewBufferString("content"))
+	if err != wechatmp.ErrUploadBodyNotSeekable {
+		t.Fatal(err)
+	}
+}
+
+func TestAddMaterial(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIMaterialAdd, "POST", "/cgi-bin/material/add_material")
+	s.Handle("/cgi-bin/material/add_material", func(r *wechatmptest.Request) interface{} {
+		return &ResultAddMaterial{MediaID: "mediaid"}
+	})
+	result, err := AddMaterial(s.NewApp(), MediaTypeVideo, "video.mp4", bytes.NewReader([]byte("video")), &VideoDescription{Title: "title", Introduction: "introduction"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MediaID != "mediaid" {
+		t.Fatal(result)
+	}
+	requests := s.Requests("/cgi-bin/material/add_material")
+	if len(requests) != 1 || requests[0].Query.Get("type") != "video" {
+		t.Fatal(requests)
+	}
+	u := parseUpload(requests[0])
+	description := &VideoDescription{}
+	err = json.Unmarshal([]byte(u.Form["description"]), description)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Filename != "video.mp4" || string(u.Content) != "video" || *description != (VideoDescription{Title: "title", Introduction: "introduction"}) {
+		t.Fatal(u)
+	}
+}
+
+func TestGetMedia(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIMediaGet, "GET", "/cgi-bin/media/get")
+	s.Handle("/cgi-bin/media/get", func(r *wechatmptest.Request) interface{} {
+		if r.Query.Get("media_id") == "video" {
+			return []byte(`{"video_url":"http://example.com/video.mp4"}`)
+		}
+		return []byte("image")
+	})
+	m, err := GetMedia(s.NewApp(), "image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(m.Body) != "image" || m.VideoURL != "" {
+		t.Fatal(m)
+	}
+	m, err = GetMedia(s.NewApp(), "video")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Body != nil || m.VideoURL != "http://example.com/video.mp4" {
+		t.Fatal(m)
+	}
+}
+
+func TestGetMaterial(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIMaterialGet, "POST", "/cgi-bin/material/get_material")
+	s.Handle("/cgi-bin/material/get_material", func(r *wechatmptest.Request) interface{} {
+		params := &paramsMediaID{}
+		err := r.Unmarshal(params)
+		if err != nil {
+			panic(err)
+		}
+		switch params.MediaID {
+		case "video":
+			return []byte(`{"title":"title","description":"description","down_url":"http://example.com/video.mp4"}`)
+		case "news":
+			return []byte(`{"news_item":[{"title":"title","thumb_media_id":"thumb","show_cover_pic":1,"content":"content","url":"http://example.com/news"}]}`)
+		}
+		return []byte("image")
+	})
+	app := s.NewApp()
+	m, err := GetMaterial(app, "image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(m.Body) != "image" || m.Title != "" {
+		t.Fatal(m)
+	}
+	m, err = GetMaterial(app, "video")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Body != nil || m.Title != "title" || m.Description != "description" || m.DownURL != "http://example.com/video.mp4" {
+		t.Fatal(m)
+	}
+	m, err = GetMaterial(app, "news")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Body != nil || len(m.NewsItem) != 1 || m.NewsItem[0].ThumbMediaID != "thumb" || m.NewsItem[0].ShowCoverPic != 1 || m.NewsItem[0].URL != "http://example.com/news" {
+		t.Fatal(m)
+	}
+}
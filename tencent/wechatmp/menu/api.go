@@ -1,6 +1,8 @@
 package menu
 
 import (
+	"encoding/json"
+
 	"github.com/herb-go/fetcher"
 	"github.com/herb-go/providers/tencent/wechatmp"
 )
@@ -18,3 +20,53 @@ func GetMenu(App *wechatmp.App) (*MenuResult, error) {
 	}
 	return menu, err
 }
+
+func DeleteMenu(App *wechatmp.App) error {
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIMenuDelete, nil, nil, result)
+}
+
+type resultAddConditional struct {
+	MenuID json.Number `json:"menuid"`
+}
+
+type paramsMenuID struct {
+	MenuID string `json:"menuid"`
+}
+
+type paramsTryMatch struct {
+	UserID string `json:"user_id"`
+}
+
+// AddConditionalMenu create conditional menu and return menu id.
+func AddConditionalMenu(App *wechatmp.App, menu *ConditionalMenu) (string, error) {
+	result := &resultAddConditional{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIMenuAddConditional, nil, menu, result)
+	if err != nil {
+		return "", err
+	}
+	return result.MenuID.String(), nil
+}
+
+// DeleteConditionalMenu delete conditional menu by menu id.
+func DeleteConditionalMenu(App *wechatmp.App, menuid string) error {
+	params := &paramsMenuID{
+		MenuID: menuid,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIMenuDelConditional, nil, params, result)
+}
+
+// TryMatchMenu get menu which would be shown to given user.
+// User id could be openid or wechat id.
+func TryMatchMenu(App *wechatmp.App, userid string) (*Menu, error) {
+	params := &paramsTryMatch{
+		UserID: userid,
+	}
+	menu := New()
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIMenuTryMatch, nil, params, menu)
+	if err != nil {
+		return nil, err
+	}
+	return menu, nil
+}
@@ -2,74 +2,60 @@ package menu
 
 // Button wechat mp menu button struct
 type Button struct {
-	Name      string       `json:"name"`
-	SubButton []*SubButton `json:"sub_button"`
-	Type      *string      `json:"type"`
-	Key       *string      `json:"key"`
-	URL       *string      `json:"url"`
-	MediaID   *string      `json:"media_id"`
-	AppID     *string      `json:"appid"`
-	Pagepath  *string      `json:"pagepath"`
+	Name      string       `json:"name" toml:"name"`
+	SubButton []*SubButton `json:"sub_button" toml:"sub_button"`
+	Type      *string      `json:"type" toml:"type"`
+	Key       *string      `json:"key" toml:"key"`
+	URL       *string      `json:"url" toml:"url"`
+	MediaID   *string      `json:"media_id" toml:"media_id"`
+	AppID     *string      `json:"appid" toml:"appid"`
+	Pagepath  *string      `json:"pagepath" toml:"pagepath"`
+	ArticleID *string      `json:"article_id,omitempty" toml:"article_id"`
 }
 
-func (b *Button) Validate() (string, error) {
-	if b.Name == "" {
-		return "按钮名不能为空", nil
-	}
-	if len(b.SubButton) > 5 {
-		return "子菜单不能超过5个", nil
-	}
-	for _, v := range b.SubButton {
-		r, err := v.Validate()
-		if err != nil {
-			return "", err
-		}
-		if r != "" {
-			return r, nil
-		}
-	}
-	return "", nil
+// Validate validate button.
+// Validation errors will be returned with paths relative to button.
+func (b *Button) Validate() (ValidationErrors, error) {
+	errs := ValidationErrors{}
+	b.validate(&errs, "")
+	return validationResult(errs), nil
 }
 
 // SubButton wechat mp menu subbutton struct
 type SubButton struct {
-	Type     string  `json:"type"`
-	Name     string  `json:"name"`
-	Key      *string `json:"key"`
-	URL      *string `json:"url"`
-	MediaID  *string `json:"media_id"`
-	AppID    *string `json:"appid"`
-	Pagepath *string `json:"pagepath"`
+	Type      string  `json:"type" toml:"type"`
+	Name      string  `json:"name" toml:"name"`
+	Key       *string `json:"key" toml:"key"`
+	URL       *string `json:"url" toml:"url"`
+	MediaID   *string `json:"media_id" toml:"media_id"`
+	AppID     *string `json:"appid" toml:"appid"`
+	Pagepath  *string `json:"pagepath" toml:"pagepath"`
+	ArticleID *string `json:"article_id,omitempty" toml:"article_id"`
 }
 
-func (b *SubButton) Validate() (string, error) {
-	if b.Name == "" {
-		return "子菜单名不能为空", nil
-	}
-	return "", nil
+// Validate validate sub button.
+// Validation errors will be returned with paths relative to sub button.
+func (b *SubButton) Validate() (ValidationErrors, error) {
+	errs := ValidationErrors{}
+	b.validate(&errs, "")
+	return validationResult(errs), nil
 }
 
 // Menu wechat mp menu struct
 type Menu struct {
-	Button []*Button `json:"button"`
+	Button []*Button `json:"button" toml:"button"`
+	// MenuID menu id,only returned by server.
+	MenuID int64 `json:"menuid,omitempty" toml:"-"`
 }
 
-func (m *Menu) Validate() (string, error) {
-	if len(m.Button) > 3 {
-		return "菜单不能超过3个", nil
-	}
-	for _, v := range m.Button {
-		r, err := v.Validate()
-		if err != nil {
-			return "", err
-		}
-		if r != "" {
-			return r, nil
-		}
-	}
-	return "", nil
-
+// Validate validate menu.
+// Nil will be returned if menu is valid.
+func (m *Menu) Validate() (ValidationErrors, error) {
+	errs := ValidationErrors{}
+	m.validate(&errs, "")
+	return validationResult(errs), nil
 }
+
 func (m *Menu) NewButton() *Button {
 	b := &Button{
 		SubButton: []*SubButton{},
@@ -78,8 +64,48 @@ func (m *Menu) NewButton() *Button {
 	return b
 }
 
+const ClientPlatformTypeIOS = "1"
+const ClientPlatformTypeAndroid = "2"
+const ClientPlatformTypeOthers = "3"
+
+const SexMale = "1"
+const SexFemale = "2"
+
+// MatchRule wechat mp conditional menu match rule struct
+type MatchRule struct {
+	TagID              string `json:"tag_id,omitempty" toml:"tag_id"`
+	Sex                string `json:"sex,omitempty" toml:"sex"`
+	Country            string `json:"country,omitempty" toml:"country"`
+	Province           string `json:"province,omitempty" toml:"province"`
+	City               string `json:"city,omitempty" toml:"city"`
+	ClientPlatformType string `json:"client_platform_type,omitempty" toml:"client_platform_type"`
+	Language           string `json:"language,omitempty" toml:"language"`
+}
+
+// ConditionalMenu wechat mp conditional menu struct
+type ConditionalMenu struct {
+	Menu
+	MatchRule *MatchRule `json:"matchrule" toml:"matchrule"`
+}
+
+// Validate validate conditional menu.
+// Nil will be returned if menu is valid.
+func (m *ConditionalMenu) Validate() (ValidationErrors, error) {
+	errs := ValidationErrors{}
+	m.validate(&errs, "")
+	return validationResult(errs), nil
+}
+
+func NewConditionalMenu() *ConditionalMenu {
+	return &ConditionalMenu{
+		Menu:      *New(),
+		MatchRule: &MatchRule{},
+	}
+}
+
 type MenuResult struct {
-	Menu *Menu `json:"menu"`
+	Menu            *Menu              `json:"menu" toml:"menu"`
+	ConditionalMenu []*ConditionalMenu `json:"conditionalmenu" toml:"conditionalmenu"`
 }
 
 func New() *Menu {
@@ -91,6 +117,7 @@ func New() *Menu {
 
 func NewMenuResult() *MenuResult {
 	return &MenuResult{
-		Menu: New(),
+		Menu:            New(),
+		ConditionalMenu: []*ConditionalMenu{},
 	}
 }
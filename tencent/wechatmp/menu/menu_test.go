@@ -0,0 +1,50 @@
+package menu
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMenuResult(t *testing.T) {
+	data := []byte(`{
+	"menu": {
+		"button": [{"type": "click", "name": "today", "key": "V1001_TODAY_MUSIC", "sub_button": []}],
+		"menuid": 208396938
+	},
+	"conditionalmenu": [{
+		"button": [{"type": "view", "name": "search", "url": "http://www.soso.com/", "sub_button": []}],
+		"matchrule": {"tag_id": "2", "client_platform_type": "2", "language": "zh_CN"},
+		"menuid": 208396993
+	}]
+}`)
+	result := NewMenuResult()
+	err := json.Unmarshal(data, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Menu.MenuID != 208396938 || len(result.Menu.Button) != 1 || *result.Menu.Button[0].Key != "V1001_TODAY_MUSIC" {
+		t.Fatal(result.Menu)
+	}
+	if len(result.ConditionalMenu) != 1 {
+		t.Fatal(result.ConditionalMenu)
+	}
+	c := result.ConditionalMenu[0]
+	if c.MenuID != 208396993 || *c.Button[0].URL != "http://www.soso.com/" {
+		t.Fatal(c)
+	}
+	if c.MatchRule.TagID != "2" || c.MatchRule.ClientPlatformType != ClientPlatformTypeAndroid || c.MatchRule.Language != "zh_CN" {
+		t.Fatal(c.MatchRule)
+	}
+	bs, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := map[string]interface{}{}
+	err = json.Unmarshal(bs, &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["button"] == nil || m["matchrule"] == nil {
+		t.Fatal(string(bs))
+	}
+}
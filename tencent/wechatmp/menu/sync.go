@@ -0,0 +1,184 @@
+package menu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/herb-go/providers/herb/statictoml"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+// ErrUnsupportedFileType error raised when menu file is neither json nor static toml.
+var ErrUnsupportedFileType = errors.New("menu: menu file must end with \".json\" or " + strconv.Quote(statictoml.Suffix))
+
+// LoadFile load menu from file.
+// File with ".json" suffix will be loaded as json,
+// and file with ".static.toml" suffix will be loaded as static toml source.
+// ErrUnsupportedFileType will be returned for other files.
+func LoadFile(path string) (*Menu, error) {
+	m := New()
+	switch {
+	case strings.HasSuffix(path, ".json"):
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, m)
+		if err != nil {
+			return nil, err
+		}
+	case strings.HasSuffix(path, statictoml.Suffix):
+		err := statictoml.Source(path).Load(m)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w (%s)", ErrUnsupportedFileType, path)
+	}
+	return m, nil
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func diffField(diff *[]string, path string, current string, desired string) {
+	if current != desired {
+		*diff = append(*diff, path+": "+strconv.Quote(current)+" -> "+strconv.Quote(desired))
+	}
+}
+
+func diffAction(diff *[]string, path string, current *action, desired *action) {
+	diffField(diff, joinPath(path, "type"), current.Type, desired.Type)
+	diffField(diff, joinPath(path, "key"), stringValue(current.Key), stringValue(desired.Key))
+	diffField(diff, joinPath(path, "url"), stringValue(current.URL), stringValue(desired.URL))
+	diffField(diff, joinPath(path, "media_id"), stringValue(current.MediaID), stringValue(desired.MediaID))
+	diffField(diff, joinPath(path, "appid"), stringValue(current.AppID), stringValue(desired.AppID))
+	diffField(diff, joinPath(path, "pagepath"), stringValue(current.Pagepath), stringValue(desired.Pagepath))
+	diffField(diff, joinPath(path, "article_id"), stringValue(current.ArticleID), stringValue(desired.ArticleID))
+}
+
+func (b *Button) action() *action {
+	return &action{
+		Type:      stringValue(b.Type),
+		Key:       b.Key,
+		URL:       b.URL,
+		MediaID:   b.MediaID,
+		AppID:     b.AppID,
+		Pagepath:  b.Pagepath,
+		ArticleID: b.ArticleID,
+	}
+}
+
+func (b *SubButton) action() *action {
+	return &action{
+		Type:      b.Type,
+		Key:       b.Key,
+		URL:       b.URL,
+		MediaID:   b.MediaID,
+		AppID:     b.AppID,
+		Pagepath:  b.Pagepath,
+		ArticleID: b.ArticleID,
+	}
+}
+
+func diffButton(diff *[]string, path string, current *Button, desired *Button) {
+	diffField(diff, joinPath(path, "name"), current.Name, desired.Name)
+	diffAction(diff, path, current.action(), desired.action())
+	for k := 0; k < len(current.SubButton) || k < len(desired.SubButton); k++ {
+		p := indexPath(path, "sub_button", k)
+		if k >= len(desired.SubButton) {
+			*diff = append(*diff, p+": removed "+strconv.Quote(current.SubButton[k].Name))
+			continue
+		}
+		if k >= len(current.SubButton) {
+			*diff = append(*diff, p+": added "+strconv.Quote(desired.SubButton[k].Name))
+			continue
+		}
+		diffField(diff, joinPath(p, "name"), current.SubButton[k].Name, desired.SubButton[k].Name)
+		diffAction(diff, p, current.SubButton[k].action(), desired.SubButton[k].action())
+	}
+}
+
+// Diff compare current menu with desired menu.
+// Readable difference lines will be returned,or nil if menus are same.
+func Diff(current *Menu, desired *Menu) []string {
+	var diff []string
+	for k := 0; k < len(current.Button) || k < len(desired.Button); k++ {
+		p := indexPath("", "button", k)
+		if k >= len(desired.Button) {
+			diff = append(diff, p+": removed "+strconv.Quote(current.Button[k].Name))
+			continue
+		}
+		if k >= len(current.Button) {
+			diff = append(diff, p+": added "+strconv.Quote(desired.Button[k].Name))
+			continue
+		}
+		diffButton(&diff, p, current.Button[k], desired.Button[k])
+	}
+	return diff
+}
+
+// SyncResult menu sync result
+type SyncResult struct {
+	// Changed whether menu was created.
+	Changed bool
+	// Diff readable difference between current menu and desired menu.
+	Diff []string
+}
+
+// String return readable diff.
+func (r *SyncResult) String() string {
+	if len(r.Diff) == 0 {
+		return "menu not changed"
+	}
+	return strings.Join(r.Diff, "\n")
+}
+
+// Sync validate menu,compare it with current menu,
+// and create menu only when something differs.
+// ValidationErrors will be returned as error if menu is invalid.
+func Sync(App *wechatmp.App, menu *Menu) (*SyncResult, error) {
+	errs, err := menu.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if errs != nil {
+		return nil, errs
+	}
+	current, err := GetMenu(App)
+	if err != nil {
+		return nil, err
+	}
+	if current.Menu == nil {
+		current.Menu = New()
+	}
+	result := &SyncResult{
+		Diff: Diff(current.Menu, menu),
+	}
+	if len(result.Diff) == 0 {
+		return result, nil
+	}
+	err = CreateMenu(App, menu)
+	if err != nil {
+		return nil, err
+	}
+	result.Changed = true
+	return result, nil
+}
+
+// SyncFile load menu from file and sync it.
+func SyncFile(App *wechatmp.App, path string) (*SyncResult, error) {
+	menu, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Sync(App, menu)
+}
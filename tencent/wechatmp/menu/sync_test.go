@@ -0,0 +1,104 @@
+package menu
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	path := filepath.Join(tmpdir, "menu.json")
+	err = ioutil.WriteFile(path, []byte(`{"button":[
+	{"type":"click","name":"today","key":"V1001_TODAY_MUSIC"},
+	{"name":"menu","sub_button":[
+		{"type":"view","name":"search","url":"http://www.soso.com/"},
+		{"type":"click","name":"zan","key":"V1001_GOOD"}
+	]}
+]}`), 0700)
+	if err != nil {
+		panic(err)
+	}
+	desired, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs, _ := desired.Validate()
+	if errs != nil {
+		t.Fatal(errs)
+	}
+	current, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current.MenuID = 208396938
+	for _, v := range current.Button {
+		if v.SubButton == nil {
+			v.SubButton = []*SubButton{}
+		}
+	}
+	if diff := Diff(current, desired); diff != nil {
+		t.Fatal(diff)
+	}
+	current.Button[0].Key = nil
+	current.Button[1].SubButton[0].Name = "soso"
+	current.Button[1].SubButton = current.Button[1].SubButton[:1]
+	diff := Diff(current, desired)
+	if len(diff) != 3 ||
+		diff[0] != `button[0].key: "" -> "V1001_TODAY_MUSIC"` ||
+		diff[1] != `button[1].sub_button[0].name: "soso" -> "search"` ||
+		diff[2] != `button[1].sub_button[1]: added "zan"` {
+		t.Fatal(diff)
+	}
+	diff = Diff(desired, New())
+	if len(diff) != 2 || diff[0] != `button[0]: removed "today"` {
+		t.Fatal(diff)
+	}
+	_, err = LoadFile(filepath.Join(tmpdir, "menu.static.toml"))
+	if err == nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTOMLFile(t *testing.T) {
+	desired, err := LoadFile(filepath.Join("testdata", "menu.static.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs, _ := desired.Validate()
+	if errs != nil {
+		t.Fatal(errs)
+	}
+	if len(desired.Button) != 2 ||
+		*desired.Button[0].Type != "click" || *desired.Button[0].Key != "V1001_TODAY_MUSIC" ||
+		desired.Button[1].Name != "menu" || len(desired.Button[1].SubButton) != 2 ||
+		*desired.Button[1].SubButton[0].URL != "http://www.soso.com/" || *desired.Button[1].SubButton[1].Key != "V1001_GOOD" {
+		t.Fatal(desired)
+	}
+	tmpdir, err := ioutil.TempDir("", "")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "menu.static.toml"))
+	if err != nil {
+		panic(err)
+	}
+	for _, name := range []string{"menu.toml", "menu.static.tmol", "menu"} {
+		path := filepath.Join(tmpdir, name)
+		err = ioutil.WriteFile(path, data, 0700)
+		if err != nil {
+			panic(err)
+		}
+		_, err = LoadFile(path)
+		if !errors.Is(err, ErrUnsupportedFileType) {
+			t.Fatal(name, err)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package menu
+
+import (
+	"strconv"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+// Menu limits
+const (
+	MaxButtons            = 3
+	MaxSubButtons         = 5
+	MaxButtonNameBytes    = 16
+	MaxSubButtonNameBytes = 60
+	MaxKeyBytes           = 128
+	MaxURLBytes           = 1024
+)
+
+// Button types
+const (
+	TypeClick              = "click"
+	TypeView               = "view"
+	TypeScancodePush       = "scancode_push"
+	TypeScancodeWaitmsg    = "scancode_waitmsg"
+	TypePicSysphoto        = "pic_sysphoto"
+	TypePicPhotoOrAlbum    = "pic_photo_or_album"
+	TypePicWeixin          = "pic_weixin"
+	TypeLocationSelect     = "location_select"
+	TypeMediaID            = "media_id"
+	TypeViewLimited        = "view_limited"
+	TypeArticleID          = "article_id"
+	TypeArticleViewLimited = "article_view_limited"
+	TypeMiniprogram        = "miniprogram"
+)
+
+// Validation error codes
+const (
+	ErrCodeRequired    = "required"
+	ErrCodeTooLong     = "too_long"
+	ErrCodeTooMany     = "too_many"
+	ErrCodeInvalidType = "invalid_type"
+	ErrCodeConflict    = "conflict"
+)
+
+// DefaultMessages default english messages of validation error codes.
+// Messages are formatted with path as first argument and limit as second argument.
+var DefaultMessages = map[string]string{
+	ErrCodeRequired:    "%[1]s is required",
+	ErrCodeTooLong:     "%[1]s must not be longer than %[2]d bytes",
+	ErrCodeTooMany:     "%[1]s must not have more than %[2]d items",
+	ErrCodeInvalidType: "%[1]s is not a valid button type",
+	ErrCodeConflict:    "%[1]s must not be set when sub buttons exist",
+}
+
+// ChineseMessages chinese messages of validation error codes.
+var ChineseMessages = map[string]string{
+	ErrCodeRequired:    "%[1]s 不能为空",
+	ErrCodeTooLong:     "%[1]s 不能超过%[2]d字节",
+	ErrCodeTooMany:     "%[1]s 不能超过%[2]d个",
+	ErrCodeInvalidType: "%[1]s 不是有效的按钮类型",
+	ErrCodeConflict:    "%[1]s 在有子菜单时不能设置",
+}
+
+// ValidationError menu validation error
+type ValidationError = wechatmp.ValidationError
+
+// NewValidationError create new validation error with DefaultMessages.
+func NewValidationError(path string, code string, limit int) *ValidationError {
+	return wechatmp.NewValidationError(path, code, limit, DefaultMessages)
+}
+
+// ValidationErrors validation error list
+type ValidationErrors = wechatmp.ValidationErrors
+
+func addError(errs *ValidationErrors, path string, code string, limit int) {
+	*errs = append(*errs, NewValidationError(path, code, limit))
+}
+
+func validationResult(errs ValidationErrors) ValidationErrors {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func joinPath(prefix string, field string) string {
+	if prefix == "" {
+		return field
+	}
+	return prefix + "." + field
+}
+
+func indexPath(prefix string, field string, index int) string {
+	return joinPath(prefix, field) + "[" + strconv.Itoa(index) + "]"
+}
+
+func isEmpty(v *string) bool {
+	return v == nil || *v == ""
+}
+
+func validateName(errs *ValidationErrors, path string, name string, limit int) {
+	if name == "" {
+		addError(errs, path, ErrCodeRequired, 0)
+		return
+	}
+	if len(name) > limit {
+		addError(errs, path, ErrCodeTooLong, limit)
+	}
+}
+
+func validateRequired(errs *ValidationErrors, path string, v *string, limit int) {
+	if isEmpty(v) {
+		addError(errs, path, ErrCodeRequired, 0)
+		return
+	}
+	if limit > 0 && len(*v) > limit {
+		addError(errs, path, ErrCodeTooLong, limit)
+	}
+}
+
+type action struct {
+	Type      string
+	Key       *string
+	URL       *string
+	MediaID   *string
+	AppID     *string
+	Pagepath  *string
+	ArticleID *string
+}
+
+func (a *action) validate(errs *ValidationErrors, path string) {
+	switch a.Type {
+	case "":
+		addError(errs, joinPath(path, "type"), ErrCodeRequired, 0)
+	case TypeClick, TypeScancodePush, TypeScancodeWaitmsg, TypePicSysphoto, TypePicPhotoOrAlbum, TypePicWeixin, TypeLocationSelect:
+		validateRequired(errs, joinPath(path, "key"), a.Key, MaxKeyBytes)
+	case TypeView:
+		validateRequired(errs, joinPath(path, "url"), a.URL, MaxURLBytes)
+	case TypeMiniprogram:
+		validateRequired(errs, joinPath(path, "appid"), a.AppID, 0)
+		validateRequired(errs, joinPath(path, "pagepath"), a.Pagepath, 0)
+		validateRequired(errs, joinPath(path, "url"), a.URL, MaxURLBytes)
+	case TypeMediaID, TypeViewLimited:
+		validateRequired(errs, joinPath(path, "media_id"), a.MediaID, 0)
+	case TypeArticleID, TypeArticleViewLimited:
+		validateRequired(errs, joinPath(path, "article_id"), a.ArticleID, 0)
+	default:
+		addError(errs, joinPath(path, "type"), ErrCodeInvalidType, 0)
+	}
+}
+
+func (b *SubButton) validate(errs *ValidationErrors, path string) {
+	validateName(errs, joinPath(path, "name"), b.Name, MaxSubButtonNameBytes)
+	a := &action{
+		Type:      b.Type,
+		Key:       b.Key,
+		URL:       b.URL,
+		MediaID:   b.MediaID,
+		AppID:     b.AppID,
+		Pagepath:  b.Pagepath,
+		ArticleID: b.ArticleID,
+	}
+	a.validate(errs, path)
+}
+
+func (b *Button) validate(errs *ValidationErrors, path string) {
+	validateName(errs, joinPath(path, "name"), b.Name, MaxButtonNameBytes)
+	if len(b.SubButton) == 0 {
+		a := &action{
+			Key:       b.Key,
+			URL:       b.URL,
+			MediaID:   b.MediaID,
+			AppID:     b.AppID,
+			Pagepath:  b.Pagepath,
+			ArticleID: b.ArticleID,
+		}
+		if b.Type != nil {
+			a.Type = *b.Type
+		}
+		a.validate(errs, path)
+		return
+	}
+	if !isEmpty(b.Type) {
+		addError(errs, joinPath(path, "type"), ErrCodeConflict, 0)
+	}
+	if len(b.SubButton) > MaxSubButtons {
+		addError(errs, joinPath(path, "sub_button"), ErrCodeTooMany, MaxSubButtons)
+	}
+	for k, v := range b.SubButton {
+		v.validate(errs, indexPath(path, "sub_button", k))
+	}
+}
+
+func (m *Menu) validate(errs *ValidationErrors, path string) {
+	if len(m.Button) == 0 {
+		addError(errs, joinPath(path, "button"), ErrCodeRequired, 0)
+	}
+	if len(m.Button) > MaxButtons {
+		addError(errs, joinPath(path, "button"), ErrCodeTooMany, MaxButtons)
+	}
+	for k, v := range m.Button {
+		v.validate(errs, indexPath(path, "button", k))
+	}
+}
+
+func (m *ConditionalMenu) validate(errs *ValidationErrors, path string) {
+	m.Menu.validate(errs, path)
+	if m.MatchRule == nil || *m.MatchRule == (MatchRule{}) {
+		addError(errs, joinPath(path, "matchrule"), ErrCodeRequired, 0)
+	}
+}
@@ -0,0 +1,111 @@
+package menu
+
+import (
+	"strings"
+	"testing"
+)
+
+func str(s string) *string {
+	return &s
+}
+
+func findError(errs ValidationErrors, path string, code string) *ValidationError {
+	for _, v := range errs {
+		if v.Path == path && v.Code == code {
+			return v
+		}
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	m := New()
+	errs, err := m.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || findError(errs, "button", ErrCodeRequired) == nil {
+		t.Fatal(errs)
+	}
+	b := m.NewButton()
+	b.Name = "today"
+	b.Type = str(TypeClick)
+	b.Key = str("V1001_TODAY_MUSIC")
+	b = m.NewButton()
+	b.Name = "menu"
+	b.SubButton = append(b.SubButton,
+		&SubButton{Type: TypeView, Name: "search", URL: str("http://www.soso.com/")},
+		&SubButton{Type: TypeMiniprogram, Name: "wxa", URL: str("http://mp.weixin.qq.com"), AppID: str("wx286b93c14bbf93aa"), Pagepath: str("pages/lunar/index")},
+		&SubButton{Type: TypeMediaID, Name: "media", MediaID: str("MEDIA_ID")},
+	)
+	errs, err = m.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	b.Type = str(TypeClick)
+	b.SubButton[0].URL = nil
+	b.SubButton[1].Pagepath = str("")
+	b.SubButton[2].Type = "unknown"
+	m.Button[0].Name = strings.Repeat("菜", 6)
+	m.Button[0].Key = nil
+	errs, err = m.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 6 {
+		t.Fatal(errs)
+	}
+	if findError(errs, "button[0].name", ErrCodeTooLong) == nil ||
+		findError(errs, "button[0].key", ErrCodeRequired) == nil ||
+		findError(errs, "button[1].type", ErrCodeConflict) == nil ||
+		findError(errs, "button[1].sub_button[0].url", ErrCodeRequired) == nil ||
+		findError(errs, "button[1].sub_button[1].pagepath", ErrCodeRequired) == nil ||
+		findError(errs, "button[1].sub_button[2].type", ErrCodeInvalidType) == nil {
+		t.Fatal(errs)
+	}
+	e := findError(errs, "button[0].name", ErrCodeTooLong)
+	if e.Limit != MaxButtonNameBytes || e.Error() != "button[0].name must not be longer than 16 bytes" {
+		t.Fatal(e)
+	}
+	if e.Localize(ChineseMessages) != "button[0].name 不能超过16字节" {
+		t.Fatal(e.Localize(ChineseMessages))
+	}
+	if e.Localize(map[string]string{}) != e.Message {
+		t.Fatal(e)
+	}
+
+	for i := 0; i < 5; i++ {
+		b.SubButton = append(b.SubButton, &SubButton{Type: TypeClick, Name: "click", Key: str("key")})
+	}
+	errs, _ = b.Validate()
+	if findError(errs, "sub_button", ErrCodeTooMany) == nil || findError(errs, "sub_button[2].type", ErrCodeInvalidType) == nil {
+		t.Fatal(errs)
+	}
+	m.NewButton()
+	m.NewButton()
+	errs, _ = m.Validate()
+	if findError(errs, "button", ErrCodeTooMany) == nil || findError(errs, "button[3].name", ErrCodeRequired) == nil || findError(errs, "button[3].type", ErrCodeRequired) == nil {
+		t.Fatal(errs)
+	}
+}
+
+func TestValidateConditionalMenu(t *testing.T) {
+	m := NewConditionalMenu()
+	b := m.NewButton()
+	b.Name = "today"
+	b.Type = str(TypeView)
+	b.URL = str("http://www.soso.com/")
+	errs, _ := m.Validate()
+	if len(errs) != 1 || findError(errs, "matchrule", ErrCodeRequired) == nil {
+		t.Fatal(errs)
+	}
+	m.MatchRule.TagID = "2"
+	errs, _ = m.Validate()
+	if errs != nil {
+		t.Fatal(errs)
+	}
+}
@@ -0,0 +1,184 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//AuthorizeURL wechat web oauth authorize url.
+const AuthorizeURL = "https://open.weixin.qq.com/connect/oauth2/authorize"
+
+//DefaultCookieName default name of state cookie.
+const DefaultCookieName = "wechatmp-oauth-state"
+
+//DefaultStateTTL default ttl of state cookie.
+const DefaultStateTTL = 10 * time.Minute
+
+//StateLength length of random state bytes.
+var StateLength = 16
+
+//ErrOnSuccessRequired error raised when callback handler is created without OnSuccess.
+var ErrOnSuccessRequired = errors.New("wechatmp oauth: OnSuccess is required")
+
+//BuildAuthorizeURL build web oauth authorize url with given appid,redirect url,scope and state.
+func BuildAuthorizeURL(appid string, redirect string, scope string, state string) string {
+	q := url.Values{}
+	q.Set("appid", appid)
+	q.Set("redirect_uri", redirect)
+	q.Set("response_type", "code")
+	q.Set("scope", scope)
+	q.Set("state", state)
+	//Encoded params are sorted by key,which is the order wechat required.
+	return AuthorizeURL + "?" + q.Encode() + "#wechat_redirect"
+}
+
+//NewState create new random state.
+func NewState() (string, error) {
+	buf := make([]byte, StateLength)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+//Authorizer web oauth authorizer which protects callback with state saved in cookie.
+type Authorizer struct {
+	App *wechatmp.App
+	//RedirectURL callback url which wechat redirects to.
+	RedirectURL string
+	//Scope oauth scope,ScopeSnsapiBase or ScopeSnsapiUserinfo.
+	Scope string
+	//Lang language of user info.
+	Lang string
+	//CookieName name of state cookie.
+	CookieName string
+	//CookiePath path of state cookie.
+	CookiePath string
+	//Secure whether state cookie is secure only.
+	Secure bool
+	//StateTTL ttl of state cookie.
+	StateTTL time.Duration
+	//OnSuccess func called with user info when callback succeeded.
+	//OnSuccess is required by callback handler.
+	OnSuccess func(w http.ResponseWriter, r *http.Request, info *wechatmp.Userinfo)
+}
+
+//New create new authorizer.
+func New(app *wechatmp.App, redirect string, scope string) *Authorizer {
+	return &Authorizer{
+		App:         app,
+		RedirectURL: redirect,
+		Scope:       scope,
+		CookieName:  DefaultCookieName,
+		CookiePath:  "/",
+		StateTTL:    DefaultStateTTL,
+	}
+}
+
+//AuthorizeURL build authorize url with given state.
+func (a *Authorizer) AuthorizeURL(state string) string {
+	return BuildAuthorizeURL(a.App.AppID, a.RedirectURL, a.Scope, state)
+}
+
+func (a *Authorizer) setStateCookie(w http.ResponseWriter, state string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     a.CookieName,
+		Value:    state,
+		Path:     a.CookiePath,
+		MaxAge:   maxAge,
+		Secure:   a.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+//VerifyState check if state in request query matches state cookie.
+func (a *Authorizer) VerifyState(r *http.Request) bool {
+	state := r.URL.Query().Get("state")
+	if state == "" {
+		return false
+	}
+	c, err := r.Cookie(a.CookieName)
+	if err != nil || c.Value == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(state), []byte(c.Value)) == 1
+}
+
+//ServeAuthorize generate new state,save it to cookie and redirect to authorize url.
+func (a *Authorizer) ServeAuthorize(w http.ResponseWriter, r *http.Request) {
+	state, err := NewState()
+	if err != nil {
+		panic(err)
+	}
+	a.setStateCookie(w, state, int(a.StateTTL/time.Second))
+	http.Redirect(w, r, a.AuthorizeURL(state), http.StatusFound)
+}
+
+//isErrorOauthCodeWrong check if error returned by GetUserInfo is caused by wrong oauth code.
+//GetUserInfo returns response as error if access token is not returned,so errcode is parsed from response body.
+func isErrorOauthCodeWrong(err error) bool {
+	if fetcher.CompareAPIErrCode(err, wechatmp.APIErrOauthCodeWrong) {
+		return true
+	}
+	var resp *fetcher.Response
+	if !errors.As(err, &resp) {
+		return false
+	}
+	result := &wechatmp.ResultAPIError{}
+	if json.Unmarshal(resp.BodyContent, result) != nil {
+		return false
+	}
+	return result.Errcode == wechatmp.APIErrOauthCodeWrong
+}
+
+//ServeCallback verify state,get user info by code and pass it to OnSuccess.
+//Panic with ErrOnSuccessRequired if OnSuccess is nil.
+func (a *Authorizer) ServeCallback(w http.ResponseWriter, r *http.Request) {
+	if a.OnSuccess == nil {
+		panic(ErrOnSuccessRequired)
+	}
+	if !a.VerifyState(r) {
+		http.Error(w, http.StatusText(403), 403)
+		return
+	}
+	a.setStateCookie(w, "", -1)
+	code := r.URL.Query().Get("code")
+	info, err := a.App.GetUserInfo(code, a.Scope, a.Lang)
+	if err != nil {
+		if isErrorOauthCodeWrong(err) {
+			http.Error(w, http.StatusText(400), 400)
+			return
+		}
+		panic(err)
+	}
+	if info == nil {
+		http.Error(w, http.StatusText(403), 403)
+		return
+	}
+	a.OnSuccess(w, r, info)
+}
+
+//AuthorizeHandler return handler which redirects to authorize url.
+func (a *Authorizer) AuthorizeHandler() http.Handler {
+	return http.HandlerFunc(a.ServeAuthorize)
+}
+
+//CallbackHandler return handler which handles oauth callback.
+//Panic with ErrOnSuccessRequired if OnSuccess is nil.
+func (a *Authorizer) CallbackHandler() http.Handler {
+	if a.OnSuccess == nil {
+		panic(ErrOnSuccessRequired)
+	}
+	return http.HandlerFunc(a.ServeCallback)
+}
@@ -0,0 +1,111 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+func TestBuildAuthorizeURL(t *testing.T) {
+	u := BuildAuthorizeURL("appid", "https://example.com/callback?a=b", wechatmp.ScopeSnsapiUserinfo, "state")
+	wanted := "https://open.weixin.qq.com/connect/oauth2/authorize?appid=appid&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback%3Fa%3Db&response_type=code&scope=snsapi_userinfo&state=state#wechat_redirect"
+	if u != wanted {
+		t.Fatal(u)
+	}
+}
+
+func TestState(t *testing.T) {
+	a := New(&wechatmp.App{AppID: "appid"}, "https://example.com/callback", wechatmp.ScopeSnsapiBase)
+	a.OnSuccess = func(w http.ResponseWriter, r *http.Request, info *wechatmp.Userinfo) {}
+	w := httptest.NewRecorder()
+	a.ServeAuthorize(w, httptest.NewRequest("GET", "/login", nil))
+	if w.Code != http.StatusFound {
+		t.Fatal(w.Code)
+	}
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := location.Query().Get("state")
+	if state == "" || location.Query().Get("appid") != "appid" || !strings.HasSuffix(location.String(), "#wechat_redirect") {
+		t.Fatal(location)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultCookieName || cookies[0].Value != state {
+		t.Fatal(cookies)
+	}
+	r := httptest.NewRequest("GET", "/callback?code=code&state="+state, nil)
+	r.AddCookie(cookies[0])
+	if !a.VerifyState(r) {
+		t.Fatal(r)
+	}
+	r = httptest.NewRequest("GET", "/callback?code=code&state=wrong", nil)
+	r.AddCookie(cookies[0])
+	if a.VerifyState(r) {
+		t.Fatal(r)
+	}
+	w = httptest.NewRecorder()
+	a.ServeCallback(w, r)
+	if w.Code != 403 {
+		t.Fatal(w.Code)
+	}
+	r = httptest.NewRequest("GET", "/callback?code=code&state="+state, nil)
+	if a.VerifyState(r) {
+		t.Fatal(r)
+	}
+}
+
+func TestCallback(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("code") != "code" {
+			w.Write([]byte(`{"errcode":40029,"errmsg":"invalid code"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"useraccesstoken","expires_in":7200,"refresh_token":"refreshtoken","openid":"openid","scope":"snsapi_base"}`))
+	}))
+	defer s.Close()
+	api := wechatmp.APIOauth2AccessToken
+	defer func() {
+		wechatmp.APIOauth2AccessToken = api
+	}()
+	wechatmp.APIOauth2AccessToken = fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint("GET", "/sns/oauth2/access_token")
+	a := New(&wechatmp.App{AppID: "appid"}, "https://example.com/callback", wechatmp.ScopeSnsapiBase)
+	var result *wechatmp.Userinfo
+	a.OnSuccess = func(w http.ResponseWriter, r *http.Request, info *wechatmp.Userinfo) {
+		result = info
+	}
+	h := a.CallbackHandler()
+	cookie := &http.Cookie{Name: DefaultCookieName, Value: "state"}
+	r := httptest.NewRequest("GET", "/callback?code=wrong&state=state", nil)
+	r.AddCookie(cookie)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != 400 || result != nil {
+		t.Fatal(w.Code, result)
+	}
+	_, err := a.App.GetUserInfo("wrong", wechatmp.ScopeSnsapiBase, "")
+	if _, ok := err.(*fetcher.Response); !ok {
+		t.Fatal(err)
+	}
+	r = httptest.NewRequest("GET", "/callback?code=code&state=state", nil)
+	r.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != 200 || result == nil || result.OpenID != "openid" || result.RefreshToken != "refreshtoken" {
+		t.Fatal(w.Code, result)
+	}
+}
+
+func TestCallbackHandlerWithoutOnSuccess(t *testing.T) {
+	defer func() {
+		if r := recover(); r != ErrOnSuccessRequired {
+			t.Fatal(r)
+		}
+	}()
+	New(&wechatmp.App{AppID: "appid"}, "https://example.com/callback", wechatmp.ScopeSnsapiBase).CallbackHandler()
+}
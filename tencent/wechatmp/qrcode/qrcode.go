@@ -1,6 +1,38 @@
 package qrcode
 
-import "github.com/herb-go/providers/tencent/wechatmp"
+import (
+	"errors"
+	"math"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+const ActionNameScene = "QR_SCENE"
+const ActionNameStrScene = "QR_STR_SCENE"
+const ActionNameLimitScene = "QR_LIMIT_SCENE"
+const ActionNameLimitStrScene = "QR_LIMIT_STR_SCENE"
+
+//MaxExpireSeconds max expire seconds of temporary qrcode.
+const MaxExpireSeconds = 2592000
+
+//MaxLimitSceneID max scene id of permanent qrcode.
+const MaxLimitSceneID = 100000
+
+//MaxSceneStrLength max length of scene str.
+const MaxSceneStrLength = 64
+
+//ErrUnknownActionName error raised when action name is unknown.
+var ErrUnknownActionName = errors.New("qrcode: unknown action name")
+
+//ErrInvalidSceneID error raised when scene id is missing or out of range.
+var ErrInvalidSceneID = errors.New("qrcode: invalid scene id")
+
+//ErrInvalidSceneStr error raised when scene str is empty or too long.
+var ErrInvalidSceneStr = errors.New("qrcode: invalid scene str")
+
+//ErrInvalidExpireSeconds error raised when expire seconds out of range.
+var ErrInvalidExpireSeconds = errors.New("qrcode: invalid expire seconds")
 
 type QRCodeScene struct {
 	SceneID  *int    `json:"scene_id"`
@@ -15,50 +47,135 @@ type QRCodeConfig struct {
 	ActionInfo    QRCodeActionInfo `json:"action_info"`
 }
 
+func validateSceneStr(scene *string) error {
+	if scene == nil || *scene == "" || len(*scene) > MaxSceneStrLength {
+		return ErrInvalidSceneStr
+	}
+	return nil
+}
+
+func validateExpireSeconds(expireSeconds *int64) error {
+	if expireSeconds != nil && (*expireSeconds < 0 || *expireSeconds > MaxExpireSeconds) {
+		return ErrInvalidExpireSeconds
+	}
+	return nil
+}
+
+//Validate validate qrcode config with limits of action.
+func (c *QRCodeConfig) Validate() error {
+	scene := c.ActionInfo.Scene
+	switch c.ActionName {
+	case ActionNameScene:
+		if scene.SceneID == nil || *scene.SceneID <= 0 || int64(*scene.SceneID) > math.MaxUint32 {
+			return ErrInvalidSceneID
+		}
+		return validateExpireSeconds(c.ExpireSeconds)
+	case ActionNameStrScene:
+		err := validateSceneStr(scene.SceneStr)
+		if err != nil {
+			return err
+		}
+		return validateExpireSeconds(c.ExpireSeconds)
+	case ActionNameLimitScene:
+		if scene.SceneID == nil || *scene.SceneID <= 0 || *scene.SceneID > MaxLimitSceneID {
+			return ErrInvalidSceneID
+		}
+		return nil
+	case ActionNameLimitStrScene:
+		return validateSceneStr(scene.SceneStr)
+	}
+	return ErrUnknownActionName
+}
+
 func NewQRCodeConfig() *QRCodeConfig {
 	return &QRCodeConfig{}
 }
+
+//CreateQRCode validate config and create qrcode.
 func CreateQRCode(App *wechatmp.App, c *QRCodeConfig) (*wechatmp.ResultQRCodeCreate, error) {
+	err := c.Validate()
+	if err != nil {
+		return nil, err
+	}
 	result := &wechatmp.ResultQRCodeCreate{}
-	err := App.CallJSONApiWithAccessToken(wechatmp.APIQRCodeCreate, nil, c, result)
+	err = App.CallJSONApiWithAccessToken(wechatmp.APIQRCodeCreate, nil, c, result)
 	if err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
+//CreateLimitStrScene create permanent qrcode with string scene.
 func CreateLimitStrScene(App *wechatmp.App, code string) (*wechatmp.ResultQRCodeCreate, error) {
 	str := code
 	c := NewQRCodeConfig()
-	c.ActionName = "QR_LIMIT_STR_SCENE"
+	c.ActionName = ActionNameLimitStrScene
 	c.ActionInfo.Scene.SceneStr = &str
 	return CreateQRCode(App, c)
 }
 
+//CreateLimitScene create permanent qrcode with integer scene.
 func CreateLimitScene(App *wechatmp.App, code int) (*wechatmp.ResultQRCodeCreate, error) {
 	int := code
 	c := NewQRCodeConfig()
-	c.ActionName = "QR_LIMIT_SCENE"
+	c.ActionName = ActionNameLimitScene
 	c.ActionInfo.Scene.SceneID = &int
 	return CreateQRCode(App, c)
 }
 
+//CreateStrScene create temporary qrcode with string scene.
+//Server default expire seconds will be used if expireSeconds is 0.
 func CreateStrScene(App *wechatmp.App, code string, expireSeconds int64) (*wechatmp.ResultQRCodeCreate, error) {
 	str := code
-	ex := expireSeconds
 	c := NewQRCodeConfig()
-	c.ExpireSeconds = &ex
-	c.ActionName = "QR_LIMIT_STR_SCENE"
+	if expireSeconds != 0 {
+		ex := expireSeconds
+		c.ExpireSeconds = &ex
+	}
+	c.ActionName = ActionNameStrScene
 	c.ActionInfo.Scene.SceneStr = &str
 	return CreateQRCode(App, c)
 }
 
+//CreateScene create temporary qrcode with integer scene.
+//Server default expire seconds will be used if expireSeconds is 0.
 func CreateScene(App *wechatmp.App, code int, expireSeconds int64) (*wechatmp.ResultQRCodeCreate, error) {
 	int := code
-	ex := expireSeconds
 	c := NewQRCodeConfig()
-	c.ExpireSeconds = &ex
-	c.ActionName = "QR_LIMIT_SCENE"
+	if expireSeconds != 0 {
+		ex := expireSeconds
+		c.ExpireSeconds = &ex
+	}
+	c.ActionName = ActionNameScene
 	c.ActionInfo.Scene.SceneID = &int
 	return CreateQRCode(App, c)
 }
+
+func showQRCodePreset(ticket string) *fetcher.Preset {
+	return wechatmp.APIShowQRCode.With(fetcher.SetQuery("ticket", ticket))
+}
+
+//ImageURL return url of qrcode image with given ticket.
+//Url is built from wechatmp.APIShowQRCode.
+func ImageURL(ticket string) (string, error) {
+	f := fetcher.New()
+	err := showQRCodePreset(ticket).Exec(f)
+	if err != nil {
+		return "", err
+	}
+	return f.URL.String(), nil
+}
+
+//ShowQRCode download qrcode image with given ticket.
+func ShowQRCode(App *wechatmp.App, ticket string) ([]byte, error) {
+	var data []byte
+	_, err := fetcher.DoAndParse(
+		&App.Client,
+		showQRCodePreset(ticket),
+		fetcher.Should200(fetcher.AsBytes(&data)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
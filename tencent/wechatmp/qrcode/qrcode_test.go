@@ -0,0 +1,96 @@
+package qrcode
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+func TestValidate(t *testing.T) {
+	id := func(v int) *int { return &v }
+	str := func(v string) *string { return &v }
+	ex := func(v int64) *int64 { return &v }
+	var tests = []struct {
+		action string
+		id     *int
+		str    *string
+		ex     *int64
+		err    error
+	}{
+		{ActionNameScene, id(1), nil, ex(60), nil},
+		{ActionNameScene, id(200000), nil, nil, nil},
+		{ActionNameScene, id(0), nil, nil, ErrInvalidSceneID},
+		{ActionNameScene, nil, str("str"), nil, ErrInvalidSceneID},
+		{ActionNameScene, id(1), nil, ex(MaxExpireSeconds + 1), ErrInvalidExpireSeconds},
+		{ActionNameStrScene, nil, str("login"), ex(MaxExpireSeconds), nil},
+		{ActionNameStrScene, nil, str(""), nil, ErrInvalidSceneStr},
+		{ActionNameStrScene, nil, str(strings.Repeat("a", MaxSceneStrLength+1)), nil, ErrInvalidSceneStr},
+		{ActionNameStrScene, nil, str("login"), ex(-1), ErrInvalidExpireSeconds},
+		{ActionNameLimitScene, id(MaxLimitSceneID), nil, nil, nil},
+		{ActionNameLimitScene, id(MaxLimitSceneID + 1), nil, nil, ErrInvalidSceneID},
+		{ActionNameLimitStrScene, nil, str(strings.Repeat("a", MaxSceneStrLength)), nil, nil},
+		{ActionNameLimitStrScene, nil, nil, nil, ErrInvalidSceneStr},
+		{"QR_UNKNOWN", id(1), nil, nil, ErrUnknownActionName},
+	}
+	for _, test := range tests {
+		c := NewQRCodeConfig()
+		c.ActionName = test.action
+		c.ActionInfo.Scene.SceneID = test.id
+		c.ActionInfo.Scene.SceneStr = test.str
+		c.ExpireSeconds = test.ex
+		err := c.Validate()
+		if err != test.err {
+			t.Fatal(test, err)
+		}
+	}
+}
+
+func TestImageURL(t *testing.T) {
+	u, err := ImageURL("gQH47joAAAAAAAAAASxodHRwOi8vd2VpeGluLnFxLmNvbS9xL2taZ2Z3TVRtNzJXV1Brb3ZhYmJJAAIEZ23sUwMEmm3sUw==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u != "https://mp.weixin.qq.com/cgi-bin/showqrcode?ticket=gQH47joAAAAAAAAAASxodHRwOi8vd2VpeGluLnFxLmNvbS9xL2taZ2Z3TVRtNzJXV1Brb3ZhYmJJAAIEZ23sUwMEmm3sUw%3D%3D" {
+		t.Fatal(u)
+	}
+}
+
+func TestShowQRCode(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cgi-bin/showqrcode" || r.URL.Query().Get("ticket") != "ticket=" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("image"))
+	}))
+	defer s.Close()
+	api := wechatmp.APIShowQRCode
+	defer func() {
+		wechatmp.APIShowQRCode = api
+	}()
+	wechatmp.APIShowQRCode = fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint("GET", "/cgi-bin/showqrcode")
+	u, err := ImageURL("ticket=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u != s.URL+"/cgi-bin/showqrcode?ticket=ticket%3D" {
+		t.Fatal(u)
+	}
+	app := &wechatmp.App{}
+	data, err := ShowQRCode(app, "ticket=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "image" {
+		t.Fatal(string(data))
+	}
+	_, err = ShowQRCode(app, "expired")
+	if err == nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,275 @@
+package qrlogin
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/qrcode"
+	"github.com/herb-go/providers/tencent/wechatmp/receiver"
+)
+
+//StatusPending status of attempt which is waiting for scan.
+const StatusPending = "pending"
+
+//StatusScanned status of attempt which is scanned.
+const StatusScanned = "scanned"
+
+//StatusExpired status of attempt which is expired or not found.
+const StatusExpired = "expired"
+
+//DefaultScenePrefix default prefix of qrcode scene.
+const DefaultScenePrefix = "qrlogin_"
+
+//DefaultExpireSeconds default expire seconds of login qrcode.
+const DefaultExpireSeconds = 300
+
+//SubscribeEventKeyPrefix event key prefix of subscribe event sent by scanning qrcode.
+const SubscribeEventKeyPrefix = "qrscene_"
+
+//IDLength length of random attempt id bytes.
+var IDLength = 16
+
+//Attempt login attempt
+type Attempt struct {
+	ID     string `json:"id"`
+	Ticket string `json:"ticket"`
+	//URL url of qrcode content.
+	URL string `json:"url"`
+	//ImageURL url of qrcode image.
+	ImageURL  string `json:"image_url"`
+	ExpiresAt int64  `json:"expires_at"`
+	Status    string `json:"status"`
+	OpenID    string `json:"openid,omitempty"`
+	ScannedAt int64  `json:"scanned_at,omitempty"`
+}
+
+//IsExpired check if attempt is expired at given time.
+func (a *Attempt) IsExpired(now time.Time) bool {
+	return a.ExpiresAt <= now.Unix()
+}
+
+//NewID create new random attempt id.
+func NewID() (string, error) {
+	buf := make([]byte, IDLength)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+//Login scan-to-login with official account temporary qrcode.
+type Login struct {
+	App   *wechatmp.App
+	Store Store
+	//ScenePrefix prefix of qrcode scene,used to tell login events from others.
+	ScenePrefix string
+	//ExpireSeconds expire seconds of login qrcode.
+	ExpireSeconds int64
+	//OnScan func called when attempt is scanned.
+	OnScan func(a *Attempt, msg *receiver.Message)
+	//OnSuccess func called by status handler when scanned attempt is polled.
+	//Attempt will be written as json if OnSuccess is nil.
+	OnSuccess func(w http.ResponseWriter, r *http.Request, a *Attempt)
+}
+
+//New create new login with in-memory store.
+func New(app *wechatmp.App) *Login {
+	return &Login{
+		App:           app,
+		Store:         NewMemoryStore(),
+		ScenePrefix:   DefaultScenePrefix,
+		ExpireSeconds: DefaultExpireSeconds,
+	}
+}
+
+//Create create new login attempt with temporary qrcode.
+func (l *Login) Create() (*Attempt, error) {
+	id, err := NewID()
+	if err != nil {
+		return nil, err
+	}
+	result, err := qrcode.CreateStrScene(l.App, l.ScenePrefix+id, l.ExpireSeconds)
+	if err != nil {
+		return nil, err
+	}
+	expireSeconds := l.ExpireSeconds
+	if result.ExpireSeconds != nil {
+		expireSeconds = *result.ExpireSeconds
+	}
+	imageURL, err := qrcode.ImageURL(result.Ticket)
+	if err != nil {
+		return nil, err
+	}
+	a := &Attempt{
+		ID:        id,
+		Ticket:    result.Ticket,
+		URL:       result.URL,
+		ImageURL:  imageURL,
+		ExpiresAt: time.Now().Unix() + expireSeconds,
+		Status:    StatusPending,
+	}
+	err = l.Store.Set(a)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+//Status get attempt by id.
+//Nil will be returned if attempt does not exist or is expired.
+func (l *Login) Status(id string) (*Attempt, error) {
+	if id == "" {
+		return nil, nil
+	}
+	return l.Store.Get(id)
+}
+
+//Delete delete attempt by id,so that it can not be used again.
+func (l *Login) Delete(id string) error {
+	return l.Store.Delete(id)
+}
+
+func (l *Login) attemptID(msg *receiver.Message) string {
+	if msg.MsgType != "event" || msg.Event == nil || msg.EventKey == nil {
+		return ""
+	}
+	key := *msg.EventKey
+	switch *msg.Event {
+	case "subscribe":
+		if !strings.HasPrefix(key, SubscribeEventKeyPrefix) {
+			return ""
+		}
+		key = strings.TrimPrefix(key, SubscribeEventKeyPrefix)
+	case "SCAN":
+	default:
+		return ""
+	}
+	if !strings.HasPrefix(key, l.ScenePrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(key, l.ScenePrefix)
+}
+
+//HandleMessage mark attempt as scanned if message is subscribe or scan event of login qrcode.
+//Return whether message is login event.
+func (l *Login) HandleMessage(msg *receiver.Message) (bool, error) {
+	id := l.attemptID(msg)
+	if id == "" {
+		return false, nil
+	}
+	a, err := l.Store.Get(id)
+	if err != nil {
+		return true, err
+	}
+	if a == nil || a.Status != StatusPending {
+		return true, nil
+	}
+	a.Status = StatusScanned
+	a.OpenID = msg.FromUserName
+	a.ScannedAt = time.Now().Unix()
+	ok, err := l.Store.CompareAndSet(a, StatusPending)
+	if err != nil {
+		return true, err
+	}
+	if !ok {
+		return true, nil
+	}
+	if l.OnScan != nil {
+		l.OnScan(a, msg)
+	}
+	return true, nil
+}
+
+//Handler return receiver handler which handles login events,
+//and passes other messages to next handler if next is not nil.
+func (l *Login) Handler(next receiver.Handler) receiver.Handler {
+	return func(r *http.Request, content []byte, msg *receiver.Message) {
+		ok, err := l.HandleMessage(msg)
+		if err != nil {
+			panic(err)
+		}
+		if !ok && next != nil {
+			next(r, content, msg)
+		}
+	}
+}
+
+type resultStatus struct {
+	Status string `json:"status"`
+}
+
+//ServeCreate create new attempt and write it as json.
+func (l *Login) ServeCreate(w http.ResponseWriter, r *http.Request) {
+	a, err := l.Create()
+	if err != nil {
+		panic(err)
+	}
+	writeJSON(w, a)
+}
+
+//Take delete and return scanned attempt by id,so that it can be used only once.
+//Nil will be returned if attempt is not scanned or has been taken already.
+func (l *Login) Take(id string) (*Attempt, error) {
+	if id == "" {
+		return nil, nil
+	}
+	return l.Store.Take(id, StatusScanned)
+}
+
+//ServeStatus write status of attempt with id in query as json.
+//Scanned attempt will be taken and passed to OnSuccess.
+func (l *Login) ServeStatus(w http.ResponseWriter, r *http.Request) {
+	a, err := l.Status(r.URL.Query().Get("id"))
+	if err != nil {
+		panic(err)
+	}
+	if a == nil {
+		writeJSON(w, &resultStatus{Status: StatusExpired})
+		return
+	}
+	if a.Status != StatusScanned {
+		writeJSON(w, &resultStatus{Status: a.Status})
+		return
+	}
+	a, err = l.Take(a.ID)
+	if err != nil {
+		panic(err)
+	}
+	if a == nil {
+		writeJSON(w, &resultStatus{Status: StatusExpired})
+		return
+	}
+	if l.OnSuccess != nil {
+		l.OnSuccess(w, r, a)
+		return
+	}
+	writeJSON(w, a)
+}
+
+//CreateHandler return handler which creates new attempt.
+func (l *Login) CreateHandler() http.Handler {
+	return http.HandlerFunc(l.ServeCreate)
+}
+
+//StatusHandler return handler which reports attempt status.
+func (l *Login) StatusHandler() http.Handler {
+	return http.HandlerFunc(l.ServeStatus)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(bs)
+	if err != nil {
+		panic(err)
+	}
+}
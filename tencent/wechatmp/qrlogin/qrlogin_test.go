@@ -0,0 +1,150 @@
+package qrlogin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechatmp/receiver"
+)
+
+func newTestMessage(event string, key string) *receiver.Message {
+	return &receiver.Message{
+		FromUserName: "testopenid",
+		MsgType:      "event",
+		Event:        &event,
+		EventKey:     &key,
+	}
+}
+
+func TestLogin(t *testing.T) {
+	l := New(nil)
+	var scanned *Attempt
+	l.OnScan = func(a *Attempt, msg *receiver.Message) {
+		scanned = a
+	}
+	now := time.Now().Unix()
+	err := l.Store.Set(&Attempt{ID: "scan", Status: StatusPending, ExpiresAt: now + 60})
+	if err != nil {
+		panic(err)
+	}
+	err = l.Store.Set(&Attempt{ID: "subscribe", Status: StatusPending, ExpiresAt: now + 60})
+	if err != nil {
+		panic(err)
+	}
+	err = l.Store.Set(&Attempt{ID: "expired", Status: StatusPending, ExpiresAt: now - 1})
+	if err != nil {
+		panic(err)
+	}
+	var passed int
+	h := l.Handler(func(r *http.Request, content []byte, msg *receiver.Message) {
+		passed++
+	})
+	h(nil, nil, newTestMessage("SCAN", "other"))
+	h(nil, nil, newTestMessage("subscribe", ""))
+	h(nil, nil, &receiver.Message{MsgType: "text"})
+	if passed != 3 || scanned != nil {
+		t.Fatal(passed, scanned)
+	}
+	h(nil, nil, newTestMessage("SCAN", DefaultScenePrefix+"expired"))
+	h(nil, nil, newTestMessage("SCAN", DefaultScenePrefix+"notexist"))
+	if passed != 3 || scanned != nil {
+		t.Fatal(passed, scanned)
+	}
+	h(nil, nil, newTestMessage("SCAN", DefaultScenePrefix+"scan"))
+	if scanned == nil || scanned.ID != "scan" || scanned.OpenID != "testopenid" {
+		t.Fatal(scanned)
+	}
+	h(nil, nil, newTestMessage("subscribe", SubscribeEventKeyPrefix+DefaultScenePrefix+"subscribe"))
+	if scanned.ID != "subscribe" {
+		t.Fatal(scanned)
+	}
+
+	poll := func(id string) map[string]interface{} {
+		r := httptest.NewRequest("GET", "/status?id="+id, nil)
+		w := httptest.NewRecorder()
+		l.ServeStatus(w, r)
+		result := map[string]interface{}{}
+		if w.Body.Len() == 0 {
+			return result
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	err = l.Store.Set(&Attempt{ID: "pending", Status: StatusPending, ExpiresAt: now + 60})
+	if err != nil {
+		panic(err)
+	}
+	if result := poll("pending"); result["status"] != StatusPending {
+		t.Fatal(result)
+	}
+	if result := poll("expired"); result["status"] != StatusExpired {
+		t.Fatal(result)
+	}
+	if result := poll(""); result["status"] != StatusExpired {
+		t.Fatal(result)
+	}
+	if result := poll("scan"); result["status"] != StatusScanned || result["openid"] != "testopenid" {
+		t.Fatal(result)
+	}
+	if result := poll("scan"); result["status"] != StatusExpired {
+		t.Fatal(result)
+	}
+	var success *Attempt
+	l.OnSuccess = func(w http.ResponseWriter, r *http.Request, a *Attempt) {
+		success = a
+	}
+	poll("subscribe")
+	if success == nil || success.OpenID != "testopenid" {
+		t.Fatal(success)
+	}
+}
+
+func TestConcurrentPoll(t *testing.T) {
+	l := New(nil)
+	var scans int32
+	l.OnScan = func(a *Attempt, msg *receiver.Message) {
+		atomic.AddInt32(&scans, 1)
+	}
+	var successes int32
+	l.OnSuccess = func(w http.ResponseWriter, r *http.Request, a *Attempt) {
+		atomic.AddInt32(&successes, 1)
+	}
+	err := l.Store.Set(&Attempt{ID: "concurrent", Status: StatusPending, ExpiresAt: time.Now().Unix() + 60})
+	if err != nil {
+		panic(err)
+	}
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := l.HandleMessage(newTestMessage("SCAN", DefaultScenePrefix+"concurrent"))
+			if err != nil {
+				panic(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if scans != 1 {
+		t.Fatal(scans)
+	}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.ServeStatus(httptest.NewRecorder(), httptest.NewRequest("GET", "/status?id=concurrent", nil))
+		}()
+	}
+	wg.Wait()
+	if successes != 1 {
+		t.Fatal(successes)
+	}
+}
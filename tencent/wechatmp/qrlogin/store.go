@@ -0,0 +1,115 @@
+package qrlogin
+
+import (
+	"sync"
+	"time"
+)
+
+//Store login attempt store interface.
+type Store interface {
+	//Set save attempt by attempt id.
+	Set(a *Attempt) error
+	//Get get attempt by id.
+	//Nil should be returned if attempt does not exist or is expired.
+	Get(id string) (*Attempt, error)
+	//Delete delete attempt by id.
+	Delete(id string) error
+	//CompareAndSet save attempt only if status of current attempt with same id equals given status.
+	//Return whether attempt was saved.
+	CompareAndSet(a *Attempt, status string) (bool, error)
+	//Take delete and return attempt by id only if its status equals given status.
+	//Nil should be returned if attempt does not exist,is expired or has other status.
+	Take(id string, status string) (*Attempt, error)
+}
+
+//MemoryStore in-memory login attempt store.
+type MemoryStore struct {
+	lock     sync.Mutex
+	attempts map[string]*Attempt
+}
+
+//NewMemoryStore create new in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		attempts: map[string]*Attempt{},
+	}
+}
+
+//Set save attempt by attempt id.
+//Expired attempts will be removed.
+func (s *MemoryStore) Set(a *Attempt) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := time.Now()
+	for k, v := range s.attempts {
+		if v.IsExpired(now) {
+			delete(s.attempts, k)
+		}
+	}
+	attempt := *a
+	s.attempts[a.ID] = &attempt
+	return nil
+}
+
+//get return attempt by id.
+//Expired attempt will be removed.
+//Lock should be held by caller.
+func (s *MemoryStore) get(id string) *Attempt {
+	a := s.attempts[id]
+	if a == nil {
+		return nil
+	}
+	if a.IsExpired(time.Now()) {
+		delete(s.attempts, id)
+		return nil
+	}
+	return a
+}
+
+//Get get attempt by id.
+//Nil will be returned if attempt does not exist or is expired.
+func (s *MemoryStore) Get(id string) (*Attempt, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	a := s.get(id)
+	if a == nil {
+		return nil, nil
+	}
+	attempt := *a
+	return &attempt, nil
+}
+
+//Delete delete attempt by id.
+func (s *MemoryStore) Delete(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.attempts, id)
+	return nil
+}
+
+//CompareAndSet save attempt only if status of current attempt with same id equals given status.
+//Return whether attempt was saved.
+func (s *MemoryStore) CompareAndSet(a *Attempt, status string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	current := s.get(a.ID)
+	if current == nil || current.Status != status {
+		return false, nil
+	}
+	attempt := *a
+	s.attempts[a.ID] = &attempt
+	return true, nil
+}
+
+//Take delete and return attempt by id only if its status equals given status.
+//Nil will be returned if attempt does not exist,is expired or has other status.
+func (s *MemoryStore) Take(id string, status string) (*Attempt, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	a := s.get(id)
+	if a == nil || a.Status != status {
+		return nil, nil
+	}
+	delete(s.attempts, id)
+	return a, nil
+}
@@ -0,0 +1,54 @@
+package subscribemessage
+
+import "encoding/xml"
+
+//EventSubscribeMsgPopup event sent when user operated subscribe popup.
+const EventSubscribeMsgPopup = "subscribe_msg_popup_event"
+
+//EventSubscribeMsgChange event sent when user changed subscribe in settings.
+const EventSubscribeMsgChange = "subscribe_msg_change_event"
+
+const SubscribeStatusAccept = "accept"
+const SubscribeStatusReject = "reject"
+
+//SubscribeStatus subscribe status of template.
+type SubscribeStatus struct {
+	TemplateID            string `xml:"TemplateId"`
+	SubscribeStatusString string
+	//PopupScene scene of popup,only in popup event.
+	PopupScene string
+}
+
+//IsAccepted check if user accepted template.
+func (s *SubscribeStatus) IsAccepted() bool {
+	return s.SubscribeStatusString == SubscribeStatusAccept
+}
+
+//SubscribeEvent subscribe message popup or change event
+type SubscribeEvent struct {
+	ToUserName   string
+	FromUserName string
+	CreateTime   int64
+	MsgType      string
+	Event        string
+	//List subscribe status list,filled by both popup and change event.
+	List []*SubscribeStatus `xml:"-"`
+}
+
+type subscribeEvent struct {
+	SubscribeEvent
+	PopupList  []*SubscribeStatus `xml:"SubscribeMsgPopupEvent>List"`
+	ChangeList []*SubscribeStatus `xml:"SubscribeMsgChangeEvent>List"`
+}
+
+//ParseSubscribeEvent parse subscribe message popup or change event from receiver message content.
+func ParseSubscribeEvent(content []byte) (*SubscribeEvent, error) {
+	e := &subscribeEvent{}
+	err := xml.Unmarshal(content, e)
+	if err != nil {
+		return nil, err
+	}
+	result := &e.SubscribeEvent
+	result.List = append(e.PopupList, e.ChangeList...)
+	return result, nil
+}
@@ -0,0 +1,245 @@
+package subscribemessage
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/oauth"
+)
+
+//SubscribeURL one-time subscribe message authorize url.
+const SubscribeURL = "https://mp.weixin.qq.com/mp/subscribemsg"
+
+//ActionConfirm callback action when user confirmed.
+const ActionConfirm = "confirm"
+
+//ActionCancel callback action when user canceled.
+const ActionCancel = "cancel"
+
+//MaxScene max scene value of one-time subscribe message.
+const MaxScene = 10000
+
+//DefaultCookieName default name of reserved cookie.
+const DefaultCookieName = "wechatmp-subscribemsg-reserved"
+
+//DefaultReservedTTL default ttl of reserved cookie.
+const DefaultReservedTTL = 10 * time.Minute
+
+//ErrInvalidScene error raised when scene is not between 0 and MaxScene.
+var ErrInvalidScene = errors.New("subscribemessage: scene should be between 0 and 10000")
+
+//ErrOnCallbackRequired error raised when callback handler is created without OnCallback.
+var ErrOnCallbackRequired = errors.New("subscribemessage: OnCallback is required")
+
+//ValidateScene check if scene is between 0 and MaxScene.
+func ValidateScene(scene int) error {
+	if scene < 0 || scene > MaxScene {
+		return ErrInvalidScene
+	}
+	return nil
+}
+
+//BuildSubscribeURL build one-time subscribe authorize url.
+//Reserved will be passed back to callback and should be used to prevent csrf.
+//ErrInvalidScene will be returned if scene is not between 0 and MaxScene.
+func BuildSubscribeURL(appid string, scene int, templateID string, redirect string, reserved string) (string, error) {
+	err := ValidateScene(scene)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	q.Set("action", "get_confirm")
+	q.Set("appid", appid)
+	q.Set("scene", strconv.Itoa(scene))
+	q.Set("template_id", templateID)
+	q.Set("redirect_url", redirect)
+	q.Set("reserved", reserved)
+	return SubscribeURL + "?" + q.Encode() + "#wechat_redirect", nil
+}
+
+//Callback one-time subscribe callback params.
+type Callback struct {
+	OpenID     string
+	TemplateID string
+	Action     string
+	Scene      int
+	Reserved   string
+}
+
+//IsConfirmed check if user confirmed subscribe.
+func (c *Callback) IsConfirmed() bool {
+	return c.Action == ActionConfirm
+}
+
+//ParseCallback parse callback params from query.
+func ParseCallback(q url.Values) *Callback {
+	scene, _ := strconv.Atoi(q.Get("scene"))
+	return &Callback{
+		OpenID:     q.Get("openid"),
+		TemplateID: q.Get("template_id"),
+		Action:     q.Get("action"),
+		Scene:      scene,
+		Reserved:   q.Get("reserved"),
+	}
+}
+
+//OneTimeMessageData data of one-time subscribe message.
+type OneTimeMessageData struct {
+	Content *OneTimeMessageContent `json:"content"`
+}
+
+//OneTimeMessageContent content of one-time subscribe message.
+type OneTimeMessageContent struct {
+	Value string `json:"value"`
+	Color string `json:"color,omitempty"`
+}
+
+//OneTimeMessage one-time subscribe message
+type OneTimeMessage struct {
+	ToUser      string              `json:"touser"`
+	TemplateID  string              `json:"template_id"`
+	URL         string              `json:"url,omitempty"`
+	Miniprogram *Miniprogram        `json:"miniprogram,omitempty"`
+	Scene       string              `json:"scene"`
+	Title       string              `json:"title"`
+	Data        *OneTimeMessageData `json:"data"`
+}
+
+//NewOneTimeMessage create one-time subscribe message to callback user.
+//Title should be no longer than 15 characters and content no longer than 200 characters.
+func NewOneTimeMessage(c *Callback, title string, content string) *OneTimeMessage {
+	return &OneTimeMessage{
+		ToUser:     c.OpenID,
+		TemplateID: c.TemplateID,
+		Scene:      strconv.Itoa(c.Scene),
+		Title:      title,
+		Data: &OneTimeMessageData{
+			Content: &OneTimeMessageContent{
+				Value: content,
+			},
+		},
+	}
+}
+
+//SendOneTime send one-time subscribe message.
+func SendOneTime(App *wechatmp.App, m *OneTimeMessage) error {
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIMessageTemplateSubscribe, nil, m, result)
+}
+
+//Authorizer one-time subscribe authorizer which protects callback with reserved value saved in cookie.
+type Authorizer struct {
+	App        *wechatmp.App
+	TemplateID string
+	//Scene scene value between 0 and MaxScene.
+	Scene int
+	//RedirectURL callback url which wechat redirects to.
+	RedirectURL string
+	//CookieName name of reserved cookie.
+	CookieName string
+	//CookiePath path of reserved cookie.
+	CookiePath string
+	//Secure whether reserved cookie is secure only.
+	Secure bool
+	//ReservedTTL ttl of reserved cookie.
+	ReservedTTL time.Duration
+	//OnCallback func called with verified callback.
+	//OnCallback is required by callback handler.
+	OnCallback func(w http.ResponseWriter, r *http.Request, c *Callback)
+}
+
+//NewAuthorizer create new one-time subscribe authorizer.
+func NewAuthorizer(app *wechatmp.App, templateID string, scene int, redirect string) *Authorizer {
+	return &Authorizer{
+		App:         app,
+		TemplateID:  templateID,
+		Scene:       scene,
+		RedirectURL: redirect,
+		CookieName:  DefaultCookieName,
+		CookiePath:  "/",
+		ReservedTTL: DefaultReservedTTL,
+	}
+}
+
+//SubscribeURL build subscribe url with given reserved value.
+//ErrInvalidScene will be returned if scene is not between 0 and MaxScene.
+func (a *Authorizer) SubscribeURL(reserved string) (string, error) {
+	return BuildSubscribeURL(a.App.AppID, a.Scene, a.TemplateID, a.RedirectURL, reserved)
+}
+
+func (a *Authorizer) setReservedCookie(w http.ResponseWriter, reserved string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     a.CookieName,
+		Value:    reserved,
+		Path:     a.CookiePath,
+		MaxAge:   maxAge,
+		Secure:   a.Secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+//VerifyCallback check if callback matches authorizer and reserved cookie.
+func (a *Authorizer) VerifyCallback(r *http.Request, c *Callback) bool {
+	if c.Reserved == "" || c.OpenID == "" || c.TemplateID != a.TemplateID {
+		return false
+	}
+	cookie, err := r.Cookie(a.CookieName)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(c.Reserved), []byte(cookie.Value)) == 1
+}
+
+//ServeSubscribe generate new reserved value,save it to cookie and redirect to subscribe url.
+func (a *Authorizer) ServeSubscribe(w http.ResponseWriter, r *http.Request) {
+	reserved, err := oauth.NewState()
+	if err != nil {
+		panic(err)
+	}
+	u, err := a.SubscribeURL(reserved)
+	if err != nil {
+		panic(err)
+	}
+	a.setReservedCookie(w, reserved, int(a.ReservedTTL/time.Second))
+	http.Redirect(w, r, u, http.StatusFound)
+}
+
+//ServeCallback verify callback and pass it to OnCallback.
+//Panic with ErrOnCallbackRequired if OnCallback is nil.
+func (a *Authorizer) ServeCallback(w http.ResponseWriter, r *http.Request) {
+	if a.OnCallback == nil {
+		panic(ErrOnCallbackRequired)
+	}
+	c := ParseCallback(r.URL.Query())
+	if !a.VerifyCallback(r, c) {
+		http.Error(w, http.StatusText(403), 403)
+		return
+	}
+	a.setReservedCookie(w, "", -1)
+	a.OnCallback(w, r, c)
+}
+
+//SubscribeHandler return handler which redirects to subscribe url.
+//Panic with ErrInvalidScene if scene is not between 0 and MaxScene.
+func (a *Authorizer) SubscribeHandler() http.Handler {
+	err := ValidateScene(a.Scene)
+	if err != nil {
+		panic(err)
+	}
+	return http.HandlerFunc(a.ServeSubscribe)
+}
+
+//CallbackHandler return handler which handles subscribe callback.
+//Panic with ErrOnCallbackRequired if OnCallback is nil.
+func (a *Authorizer) CallbackHandler() http.Handler {
+	if a.OnCallback == nil {
+		panic(ErrOnCallbackRequired)
+	}
+	return http.HandlerFunc(a.ServeCallback)
+}
@@ -0,0 +1,82 @@
+package subscribemessage
+
+import (
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/templatemessage"
+)
+
+const TemplateTypeOneTime = 2
+const TemplateTypeLongTerm = 3
+
+//Miniprogram miniprogram opened when message clicked.
+type Miniprogram struct {
+	AppID    string `json:"appid"`
+	PagePath string `json:"pagepath"`
+}
+
+//Message long-term or one-time subscribe message
+type Message struct {
+	ToUser      string               `json:"touser"`
+	TemplateID  string               `json:"template_id"`
+	Page        string               `json:"page,omitempty"`
+	Miniprogram *Miniprogram         `json:"miniprogram,omitempty"`
+	Data        templatemessage.Data `json:"data"`
+}
+
+//NewMessage create new subscribe message.
+func NewMessage(touser string, templateID string, data templatemessage.Data) *Message {
+	return &Message{
+		ToUser:     touser,
+		TemplateID: templateID,
+		Data:       data,
+	}
+}
+
+//Template subscribe message template
+type Template struct {
+	PriTmplID string `json:"priTmplId"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Example   string `json:"example"`
+	Type      int    `json:"type"`
+}
+
+//Category category of official account
+type Category struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type resultTemplates struct {
+	Data []*Template `json:"data"`
+}
+
+type resultCategories struct {
+	Data []*Category `json:"data"`
+}
+
+//Send send subscribe message to user who subscribed template.
+func Send(App *wechatmp.App, m *Message) error {
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIMessageSubscribeBizSend, nil, m, result)
+}
+
+//GetTemplates get subscribe message templates added to official account.
+func GetTemplates(App *wechatmp.App) ([]*Template, error) {
+	result := &resultTemplates{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APINewTmplGetTemplate, nil, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+//GetCategories get categories of official account.
+func GetCategories(App *wechatmp.App) ([]*Category, error) {
+	result := &resultCategories{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APINewTmplGetCategory, nil, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
@@ -0,0 +1,139 @@
+package subscribemessage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+func TestParseSubscribeEvent(t *testing.T) {
+	e, err := ParseSubscribeEvent([]byte(`<xml>
+<ToUserName><![CDATA[gh_123456789abc]]></ToUserName>
+<FromUserName><![CDATA[otFpruAK8D-E6EfStSYonYSBZ8_4]]></FromUserName>
+<CreateTime>1610969440</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[subscribe_msg_popup_event]]></Event>
+<SubscribeMsgPopupEvent>
+<List>
+<TemplateId><![CDATA[VRR0UEO9VJOLs0MHlU0OilqX6MVFDwH3_3gz3Oc0NIc]]></TemplateId>
+<SubscribeStatusString><![CDATA[accept]]></SubscribeStatusString>
+<PopupScene>2</PopupScene>
+</List>
+<List>
+<TemplateId><![CDATA[9nLIlbOQZC5Y89AZteFEux3WCXRRRG5Wfzkpssu4bLI]]></TemplateId>
+<SubscribeStatusString><![CDATA[reject]]></SubscribeStatusString>
+<PopupScene>2</PopupScene>
+</List>
+</SubscribeMsgPopupEvent>
+</xml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Event != EventSubscribeMsgPopup || e.FromUserName != "otFpruAK8D-E6EfStSYonYSBZ8_4" || len(e.List) != 2 {
+		t.Fatal(e)
+	}
+	if !e.List[0].IsAccepted() || e.List[1].IsAccepted() || e.List[1].TemplateID != "9nLIlbOQZC5Y89AZteFEux3WCXRRRG5Wfzkpssu4bLI" || e.List[0].PopupScene != "2" {
+		t.Fatal(e.List)
+	}
+	e, err = ParseSubscribeEvent([]byte(`<xml>
+<Event><![CDATA[subscribe_msg_change_event]]></Event>
+<SubscribeMsgChangeEvent>
+<List>
+<TemplateId><![CDATA[VRR0UEO9VJOLs0MHlU0OilqX6MVFDwH3_3gz3Oc0NIc]]></TemplateId>
+<SubscribeStatusString><![CDATA[reject]]></SubscribeStatusString>
+</List>
+</SubscribeMsgChangeEvent>
+</xml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Event != EventSubscribeMsgChange || len(e.List) != 1 || e.List[0].IsAccepted() {
+		t.Fatal(e)
+	}
+}
+
+func TestAuthorizer(t *testing.T) {
+	app := &wechatmp.App{AppID: "testappid"}
+	a := NewAuthorizer(app, "testtemplate", 1000, "https://example.com/callback")
+	var callback *Callback
+	a.OnCallback = func(w http.ResponseWriter, r *http.Request, c *Callback) {
+		callback = c
+	}
+	w := httptest.NewRecorder()
+	a.ServeSubscribe(w, httptest.NewRequest("GET", "/subscribe", nil))
+	if w.Code != http.StatusFound {
+		t.Fatal(w.Code)
+	}
+	u, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("action") != "get_confirm" || q.Get("appid") != "testappid" || q.Get("scene") != "1000" || q.Get("template_id") != "testtemplate" || q.Get("redirect_url") != "https://example.com/callback" || u.Fragment != "wechat_redirect" {
+		t.Fatal(u)
+	}
+	reserved := q.Get("reserved")
+	cookies := w.Result().Cookies()
+	if reserved == "" || len(cookies) != 1 || cookies[0].Value != reserved {
+		t.Fatal(cookies)
+	}
+	serve := func(query string, withCookie bool) int {
+		r := httptest.NewRequest("GET", "/callback?"+query, nil)
+		if withCookie {
+			r.AddCookie(cookies[0])
+		}
+		w := httptest.NewRecorder()
+		a.ServeCallback(w, r)
+		return w.Code
+	}
+	if code := serve("openid=testopenid&template_id=testtemplate&action=confirm&scene=1000&reserved="+reserved, false); code != 403 || callback != nil {
+		t.Fatal(code)
+	}
+	if code := serve("openid=testopenid&template_id=testtemplate&action=confirm&scene=1000&reserved=wrong", true); code != 403 || callback != nil {
+		t.Fatal(code)
+	}
+	if code := serve("openid=testopenid&template_id=other&action=confirm&scene=1000&reserved="+reserved, true); code != 403 || callback != nil {
+		t.Fatal(code)
+	}
+	serve("openid=testopenid&template_id=testtemplate&action=confirm&scene=1000&reserved="+reserved, true)
+	if callback == nil || !callback.IsConfirmed() || callback.OpenID != "testopenid" || callback.Scene != 1000 {
+		t.Fatal(callback)
+	}
+	m := NewOneTimeMessage(callback, "title", "content")
+	if m.ToUser != "testopenid" || m.TemplateID != "testtemplate" || m.Scene != "1000" || m.Data.Content.Value != "content" {
+		t.Fatal(m)
+	}
+}
+
+func TestAuthorizerValidation(t *testing.T) {
+	for _, scene := range []int{0, MaxScene} {
+		_, err := BuildSubscribeURL("testappid", scene, "testtemplate", "https://example.com/callback", "reserved")
+		if err != nil {
+			t.Fatal(scene, err)
+		}
+	}
+	for _, scene := range []int{-1, MaxScene + 1} {
+		_, err := BuildSubscribeURL("testappid", scene, "testtemplate", "https://example.com/callback", "reserved")
+		if err != ErrInvalidScene {
+			t.Fatal(scene, err)
+		}
+	}
+	mustPanic := func(wanted error, f func()) {
+		defer func() {
+			if r := recover(); r != wanted {
+				t.Fatal(r)
+			}
+		}()
+		f()
+	}
+	app := &wechatmp.App{AppID: "testappid"}
+	mustPanic(ErrInvalidScene, func() {
+		NewAuthorizer(app, "testtemplate", MaxScene+1, "https://example.com/callback").SubscribeHandler()
+	})
+	mustPanic(ErrOnCallbackRequired, func() {
+		NewAuthorizer(app, "testtemplate", 1000, "https://example.com/callback").CallbackHandler()
+	})
+}
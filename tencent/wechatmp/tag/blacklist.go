@@ -0,0 +1,61 @@
+package tag
+
+import (
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//BatchBlacklistLimit max openid count of one batch blacklist or unblacklist request.
+const BatchBlacklistLimit = 20
+
+type paramsGetBlacklist struct {
+	BeginOpenID string `json:"begin_openid"`
+}
+
+//GetBlacklist get one page of blacklisted users after given openid.
+//First page will be returned if beginOpenID is empty.
+func GetBlacklist(App *wechatmp.App, beginOpenID string) (*UserListResult, error) {
+	params := &paramsGetBlacklist{
+		BeginOpenID: beginOpenID,
+	}
+	result := &UserListResult{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APITagsMembersGetBlackList, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type paramsBatchBlacklist struct {
+	OpenIDList []string `json:"openid_list"`
+}
+
+func batchBlacklist(App *wechatmp.App, api *fetcher.Preset, openids []string) error {
+	for start := 0; start < len(openids); start += BatchBlacklistLimit {
+		end := start + BatchBlacklistLimit
+		if end > len(openids) {
+			end = len(openids)
+		}
+		params := &paramsBatchBlacklist{
+			OpenIDList: openids[start:end],
+		}
+		result := &wechatmp.ResultAPIError{}
+		err := App.CallJSONApiWithAccessToken(api, nil, params, result)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//BatchBlacklist add users to blacklist.
+//Openid list will be split into requests with at most BatchBlacklistLimit openids.
+func BatchBlacklist(App *wechatmp.App, openids ...string) error {
+	return batchBlacklist(App, wechatmp.APITagsMembersBatchBlackList, openids)
+}
+
+//BatchUnblacklist remove users from blacklist.
+//Openid list will be split into requests with at most BatchBlacklistLimit openids.
+func BatchUnblacklist(App *wechatmp.App, openids ...string) error {
+	return batchBlacklist(App, wechatmp.APITagsMembersBatchUnblackList, openids)
+}
@@ -0,0 +1,172 @@
+package tag
+
+import (
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//BatchTaggingLimit max openid count of one batch tagging or untagging request.
+const BatchTaggingLimit = 50
+
+//APIErrTagNotExists api error code when tag does not exist.
+const APIErrTagNotExists = 45159
+
+//IsErrorTagNotExists check if error is raised because tag does not exist.
+func IsErrorTagNotExists(err error) bool {
+	return fetcher.CompareAPIErrCode(err, APIErrTagNotExists)
+}
+
+//Tag wechat mp user tag
+type Tag struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count,omitempty"`
+}
+
+type paramsTag struct {
+	Tag *Tag `json:"tag"`
+}
+
+type resultTag struct {
+	Tag *Tag `json:"tag"`
+}
+
+type resultTags struct {
+	Tags []*Tag `json:"tags"`
+}
+
+//OpenIDListData openid list data of user list.
+type OpenIDListData struct {
+	OpenID []string `json:"openid"`
+}
+
+//UserListResult user list result
+type UserListResult struct {
+	Total      int            `json:"total"`
+	Count      int            `json:"count"`
+	Data       OpenIDListData `json:"data"`
+	NextOpenID string         `json:"next_openid"`
+}
+
+//CreateTag create tag with given name.
+func CreateTag(App *wechatmp.App, name string) (*Tag, error) {
+	params := &paramsTag{
+		Tag: &Tag{Name: name},
+	}
+	result := &resultTag{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APITagsCreate, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Tag, nil
+}
+
+//GetTags get all tags.
+func GetTags(App *wechatmp.App) ([]*Tag, error) {
+	result := &resultTags{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APITagsGet, nil, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Tags, nil
+}
+
+//UpdateTag update name of tag.
+func UpdateTag(App *wechatmp.App, id int, name string) error {
+	params := &paramsTag{
+		Tag: &Tag{ID: id, Name: name},
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APITagsUpdate, nil, params, result)
+}
+
+type paramsTagID struct {
+	Tag struct {
+		ID int `json:"id"`
+	} `json:"tag"`
+}
+
+//DeleteTag delete tag by id.
+func DeleteTag(App *wechatmp.App, id int) error {
+	params := &paramsTagID{}
+	params.Tag.ID = id
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APITagsDelete, nil, params, result)
+}
+
+type paramsUserTagGet struct {
+	TagID      int    `json:"tagid"`
+	NextOpenID string `json:"next_openid"`
+}
+
+//GetTagUsers get one page of users with given tag after given openid.
+//First page will be returned if nextOpenID is empty.
+func GetTagUsers(App *wechatmp.App, tagid int, nextOpenID string) (*UserListResult, error) {
+	params := &paramsUserTagGet{
+		TagID:      tagid,
+		NextOpenID: nextOpenID,
+	}
+	result := &UserListResult{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIUserTagGet, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type paramsBatchTagging struct {
+	OpenIDList []string `json:"openid_list"`
+	TagID      int      `json:"tagid"`
+}
+
+func batchTagging(App *wechatmp.App, api *fetcher.Preset, tagid int, openids []string) error {
+	for start := 0; start < len(openids); start += BatchTaggingLimit {
+		end := start + BatchTaggingLimit
+		if end > len(openids) {
+			end = len(openids)
+		}
+		params := &paramsBatchTagging{
+			OpenIDList: openids[start:end],
+			TagID:      tagid,
+		}
+		result := &wechatmp.ResultAPIError{}
+		err := App.CallJSONApiWithAccessToken(api, nil, params, result)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//BatchTagging add tag to users.
+//Openid list will be split into requests with at most BatchTaggingLimit openids.
+func BatchTagging(App *wechatmp.App, tagid int, openids ...string) error {
+	return batchTagging(App, wechatmp.APITagsMembersBatchTagging, tagid, openids)
+}
+
+//BatchUntagging remove tag from users.
+//Openid list will be split into requests with at most BatchTaggingLimit openids.
+func BatchUntagging(App *wechatmp.App, tagid int, openids ...string) error {
+	return batchTagging(App, wechatmp.APITagsMembersBatchUntagging, tagid, openids)
+}
+
+type paramsOpenID struct {
+	OpenID string `json:"openid"`
+}
+
+type resultTagIDList struct {
+	TagIDList []int `json:"tagid_list"`
+}
+
+//GetUserTags get tag id list of user.
+func GetUserTags(App *wechatmp.App, openid string) ([]int, error) {
+	params := &paramsOpenID{
+		OpenID: openid,
+	}
+	result := &resultTagIDList{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APITagsGetIDList, nil, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result.TagIDList, nil
+}
@@ -0,0 +1,134 @@
+package tag
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/internal/wechatmptest"
+)
+
+func openidList(count int) []string {
+	result := []string{}
+	for i := 0; i < count; i++ {
+		result = append(result, fmt.Sprintf("openid%d", i))
+	}
+	return result
+}
+
+func TestTag(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APITagsCreate, "POST", "/cgi-bin/tags/create")
+	s.Replace(t, &wechatmp.APITagsGet, "GET", "/cgi-bin/tags/get")
+	s.Replace(t, &wechatmp.APITagsDelete, "POST", "/cgi-bin/tags/delete")
+	s.Replace(t, &wechatmp.APITagsGetIDList, "POST", "/cgi-bin/tags/getidlist")
+	s.Handle("/cgi-bin/tags/create", func(r *wechatmptest.Request) interface{} {
+		params := &paramsTag{}
+		err := r.Unmarshal(params)
+		if err != nil {
+			panic(err)
+		}
+		return &resultTag{Tag: &Tag{ID: 100, Name: params.Tag.Name}}
+	})
+	s.Handle("/cgi-bin/tags/get", func(r *wechatmptest.Request) interface{} {
+		return []byte(`{"tags":[{"id":2,"name":"star","count":10},{"id":100,"name":"tag","count":0}]}`)
+	})
+	s.Handle("/cgi-bin/tags/delete", func(r *wechatmptest.Request) interface{} {
+		params := &paramsTagID{}
+		err := r.Unmarshal(params)
+		if err != nil {
+			panic(err)
+		}
+		if params.Tag.ID != 100 {
+			return &wechatmp.ResultAPIError{Errcode: APIErrTagNotExists, Errmsg: "invalid tag id"}
+		}
+		return &wechatmp.ResultAPIError{}
+	})
+	s.Handle("/cgi-bin/tags/getidlist", func(r *wechatmptest.Request) interface{} {
+		return []byte(`{"tagid_list":[2,100]}`)
+	})
+	app := s.NewApp()
+	tag, err := CreateTag(app, "tag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.ID != 100 || tag.Name != "tag" {
+		t.Fatal(tag)
+	}
+	tags, err := GetTags(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 2 || *tags[0] != (Tag{ID: 2, Name: "star", Count: 10}) {
+		t.Fatal(tags)
+	}
+	ids, err := GetUserTags(app, "openid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []int{2, 100}) {
+		t.Fatal(ids)
+	}
+	err = DeleteTag(app, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = DeleteTag(app, 101)
+	if !IsErrorTagNotExists(err) {
+		t.Fatal(err)
+	}
+}
+
+func TestBatchTagging(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APITagsMembersBatchTagging, "POST", "/cgi-bin/tags/members/batchtagging")
+	s.Replace(t, &wechatmp.APITagsMembersBatchBlackList, "POST", "/cgi-bin/tags/members/batchblacklist")
+	s.Handle("/cgi-bin/tags/members/batchtagging", func(r *wechatmptest.Request) interface{} {
+		return &wechatmp.ResultAPIError{}
+	})
+	s.Handle("/cgi-bin/tags/members/batchblacklist", func(r *wechatmptest.Request) interface{} {
+		return &wechatmp.ResultAPIError{}
+	})
+	app := s.NewApp()
+	openids := openidList(120)
+	err := BatchTagging(app, 100, openids...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := []string{}
+	chunks := []int{}
+	for _, r := range s.Requests("/cgi-bin/tags/members/batchtagging") {
+		params := &paramsBatchTagging{}
+		err = r.Unmarshal(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if params.TagID != 100 {
+			t.Fatal(params)
+		}
+		chunks = append(chunks, len(params.OpenIDList))
+		list = append(list, params.OpenIDList...)
+	}
+	if !reflect.DeepEqual(chunks, []int{50, 50, 20}) || !reflect.DeepEqual(list, openids) {
+		t.Fatal(chunks, list)
+	}
+	err = BatchBlacklist(app, openidList(45)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks = []int{}
+	for _, r := range s.Requests("/cgi-bin/tags/members/batchblacklist") {
+		params := &paramsBatchBlacklist{}
+		err = r.Unmarshal(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, len(params.OpenIDList))
+	}
+	if !reflect.DeepEqual(chunks, []int{20, 20, 5}) {
+		t.Fatal(chunks)
+	}
+}
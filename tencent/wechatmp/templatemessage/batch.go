@@ -0,0 +1,218 @@
+package templatemessage
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//DefaultBatchConcurrency default concurrency of batch sender.
+const DefaultBatchConcurrency = 10
+
+//DefaultBatchMaxRetries default max retries of batch sender.
+const DefaultBatchMaxRetries = 3
+
+//DefaultBatchRetryDelay default delay before first retry of batch sender.
+const DefaultBatchRetryDelay = time.Second
+
+//APIErrSystemBusy errcode returned when wechat system is busy.
+const APIErrSystemBusy = -1
+
+//APIErrMinuteQuotaLimit errcode returned when api minute quota reached.
+const APIErrMinuteQuotaLimit = 45011
+
+//TransientErrCodes api errcodes which are retried by batch sender.
+var TransientErrCodes = []int{APIErrSystemBusy, APIErrMinuteQuotaLimit}
+
+//IsTransientError check if error is connecting error or api error with transient errcode.
+//Other network errors such as read timeout are not transient,
+//as message could have been delivered before error raised.
+func IsTransientError(err error) bool {
+	var operr *net.OpError
+	if errors.As(err, &operr) && operr.Op == "dial" {
+		return true
+	}
+	for _, code := range TransientErrCodes {
+		if fetcher.CompareAPIErrCode(err, code) {
+			return true
+		}
+	}
+	return false
+}
+
+//BatchResult send result of single message in batch.
+type BatchResult struct {
+	//Index index of message in batch.
+	Index  int
+	ToUser string
+	//MsgID msg id returned by server if message sent.
+	MsgID int64
+	//Err error of last attempt,nil if message sent.
+	Err error
+	//Attempts count of send attempts.
+	//Attempts failed before sending,such as validation errors,are not counted.
+	Attempts int
+}
+
+//BatchReport report of batch send.
+type BatchReport struct {
+	//Results results in same order of messages.
+	Results   []*BatchResult
+	Succeeded int
+	Failed    int
+	Duration  time.Duration
+}
+
+//Errors return results of failed messages.
+func (r *BatchReport) Errors() []*BatchResult {
+	result := []*BatchResult{}
+	for _, v := range r.Results {
+		if v.Err != nil {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+//SendFunc func which sends single template message.
+type SendFunc func(m *wechatmp.TemplateMessage) (*wechatmp.TemplateMessageSendResult, error)
+
+//BatchSender concurrent and rate-limited template message sender.
+type BatchSender struct {
+	//Concurrency max count of concurrent sending.
+	Concurrency int
+	//RatePerSecond max count of send attempts per second.
+	//No limit if not positive or greater than one per nanosecond.
+	RatePerSecond int
+	//MaxRetries max retries of transient errors.
+	MaxRetries int
+	//RetryDelay delay before first retry,doubled on every retry.
+	RetryDelay time.Duration
+	//Cache template cache used to validate messages before sending if not nil.
+	//Invalid messages will not be sent or retried,
+	//while errors raised when loading templates will be retried if they are transient.
+	Cache *TemplateCache
+	//IsTransient func which checks if error should be retried.
+	IsTransient func(err error) bool
+	//OnResult func called when message finished if not nil.
+	OnResult func(r *BatchResult)
+	send     SendFunc
+}
+
+//NewBatchSender create new batch sender which sends messages with given app.
+func NewBatchSender(app *wechatmp.App) *BatchSender {
+	return NewBatchSenderWithFunc(func(m *wechatmp.TemplateMessage) (*wechatmp.TemplateMessageSendResult, error) {
+		return SendTemplateMessage(app, m)
+	})
+}
+
+//NewBatchSenderWithFunc create new batch sender which sends messages with given func.
+func NewBatchSenderWithFunc(send SendFunc) *BatchSender {
+	return &BatchSender{
+		Concurrency: DefaultBatchConcurrency,
+		MaxRetries:  DefaultBatchMaxRetries,
+		RetryDelay:  DefaultBatchRetryDelay,
+		IsTransient: IsTransientError,
+		send:        send,
+	}
+}
+
+//attempt validate message if cache is not nil and send it.
+//Return whether message was sent to server.
+func (s *BatchSender) attempt(m *wechatmp.TemplateMessage, wait func()) (*wechatmp.TemplateMessageSendResult, bool, error) {
+	if s.Cache != nil {
+		err := s.Cache.Validate(m)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	wait()
+	result, err := s.send(m)
+	return result, true, err
+}
+
+func (s *BatchSender) sendWithRetry(index int, m *wechatmp.TemplateMessage, wait func()) *BatchResult {
+	r := &BatchResult{
+		Index:  index,
+		ToUser: m.ToUser,
+	}
+	delay := s.RetryDelay
+	for retries := 0; ; retries++ {
+		result, sent, err := s.attempt(m, wait)
+		if sent {
+			r.Attempts++
+		}
+		if err == nil {
+			r.MsgID = result.MsgID
+			r.Err = nil
+			return r
+		}
+		r.Err = err
+		if _, ok := err.(ValidationErrors); ok {
+			return r
+		}
+		if retries >= s.MaxRetries || s.IsTransient == nil || !s.IsTransient(err) {
+			return r
+		}
+		time.Sleep(delay)
+		delay = delay * 2
+	}
+}
+
+//Send send messages and return report after all messages finished.
+func (s *BatchSender) Send(messages []*wechatmp.TemplateMessage) *BatchReport {
+	start := time.Now()
+	report := &BatchReport{
+		Results: make([]*BatchResult, len(messages)),
+	}
+	wait := func() {}
+	var interval time.Duration
+	if s.RatePerSecond > 0 {
+		interval = time.Second / time.Duration(s.RatePerSecond)
+	}
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		wait = func() {
+			<-ticker.C
+		}
+	}
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	indexes := make(chan int)
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				r := s.sendWithRetry(index, messages[index], wait)
+				lock.Lock()
+				report.Results[index] = r
+				if r.Err == nil {
+					report.Succeeded++
+				} else {
+					report.Failed++
+				}
+				if s.OnResult != nil {
+					s.OnResult(r)
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for k := range messages {
+		indexes <- k
+	}
+	close(indexes)
+	wg.Wait()
+	report.Duration = time.Since(start)
+	return report
+}
@@ -0,0 +1,203 @@
+package templatemessage
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/internal/wechatmptest"
+)
+
+var errTestTransient = errors.New("transient")
+var errTestPermanent = errors.New("permanent")
+
+func TestBatchSender(t *testing.T) {
+	var lock sync.Mutex
+	attempts := map[string]int{}
+	running := 0
+	maxRunning := 0
+	s := NewBatchSenderWithFunc(func(m *wechatmp.TemplateMessage) (*wechatmp.TemplateMessageSendResult, error) {
+		lock.Lock()
+		attempts[m.ToUser]++
+		count := attempts[m.ToUser]
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		lock.Unlock()
+		time.Sleep(time.Millisecond)
+		lock.Lock()
+		running--
+		lock.Unlock()
+		switch m.ToUser {
+		case "permanent":
+			return nil, errTestPermanent
+		case "transient":
+			return nil, errTestTransient
+		case "retry":
+			if count < 2 {
+				return nil, errTestTransient
+			}
+		}
+		return &wechatmp.TemplateMessageSendResult{MsgID: int64(count)}, nil
+	})
+	s.Concurrency = 3
+	s.RatePerSecond = 1000
+	s.MaxRetries = 2
+	s.RetryDelay = time.Millisecond
+	s.IsTransient = func(err error) bool {
+		return err == errTestTransient
+	}
+	var finished int
+	s.OnResult = func(r *BatchResult) {
+		finished++
+	}
+	messages := []*wechatmp.TemplateMessage{}
+	for i := 0; i < 10; i++ {
+		m := wechatmp.NewTemplateMessage()
+		m.ToUser = strconv.Itoa(i)
+		messages = append(messages, m)
+	}
+	for _, v := range []string{"permanent", "transient", "retry"} {
+		m := wechatmp.NewTemplateMessage()
+		m.ToUser = v
+		messages = append(messages, m)
+	}
+	report := s.Send(messages)
+	if report.Succeeded != 11 || report.Failed != 2 || finished != 13 || len(report.Results) != 13 {
+		t.Fatal(report)
+	}
+	if maxRunning > 3 {
+		t.Fatal(maxRunning)
+	}
+	for k, v := range report.Results {
+		if v.Index != k || v.ToUser != messages[k].ToUser {
+			t.Fatal(v)
+		}
+	}
+	if r := report.Results[10]; r.Err != errTestPermanent || r.Attempts != 1 {
+		t.Fatal(r)
+	}
+	if r := report.Results[11]; r.Err != errTestTransient || r.Attempts != 3 {
+		t.Fatal(r)
+	}
+	if r := report.Results[12]; r.Err != nil || r.Attempts != 2 || r.MsgID != 2 {
+		t.Fatal(r)
+	}
+	errs := report.Errors()
+	if len(errs) != 2 || errs[0].ToUser != "permanent" || errs[1].ToUser != "transient" {
+		t.Fatal(errs)
+	}
+}
+
+func TestBatchSenderValidate(t *testing.T) {
+	sent := 0
+	s := NewBatchSenderWithFunc(func(m *wechatmp.TemplateMessage) (*wechatmp.TemplateMessageSendResult, error) {
+		sent++
+		return &wechatmp.TemplateMessageSendResult{MsgID: 1}, nil
+	})
+	s.Cache = NewTemplateCache(nil)
+	s.Cache.MinReloadInterval = time.Hour
+	s.Cache.Set([]wechatmp.PrivateTemplate{{TemplateID: "testtemplate", Content: "{{first.DATA}}"}})
+	valid, _ := NewMessage("valid", "testtemplate", NewData().Set("first", "first"))
+	invalid, _ := NewMessage("invalid", "testtemplate", NewData().Set("second", "second"))
+	report := s.Send([]*wechatmp.TemplateMessage{valid, invalid})
+	if sent != 1 || report.Succeeded != 1 || report.Failed != 1 {
+		t.Fatal(report)
+	}
+	if _, ok := report.Results[1].Err.(ValidationErrors); !ok || report.Results[1].Attempts != 0 {
+		t.Fatal(report.Results[1])
+	}
+}
+
+func TestBatchSenderRetryTemplateLoad(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIGetAllPrivateTemplate, "GET", "/cgi-bin/template/get_all_private_template")
+	loads := 0
+	s.Handle("/cgi-bin/template/get_all_private_template", func(r *wechatmptest.Request) interface{} {
+		loads++
+		if loads == 1 {
+			return &wechatmp.ResultAPIError{Errcode: APIErrSystemBusy, Errmsg: "system error"}
+		}
+		if loads == 3 {
+			return &wechatmp.ResultAPIError{Errcode: 40013, Errmsg: "invalid appid"}
+		}
+		return &wechatmp.AllPrivateTemplateResult{TemplateList: []wechatmp.PrivateTemplate{{TemplateID: "testtemplate", Content: "{{first.DATA}}"}}}
+	})
+	sent := 0
+	sender := NewBatchSenderWithFunc(func(m *wechatmp.TemplateMessage) (*wechatmp.TemplateMessageSendResult, error) {
+		sent++
+		return &wechatmp.TemplateMessageSendResult{MsgID: 1}, nil
+	})
+	sender.Concurrency = 1
+	sender.RetryDelay = time.Millisecond
+	sender.Cache = NewTemplateCache(s.NewApp())
+	sender.Cache.MinReloadInterval = 0
+	valid, _ := NewMessage("valid", "testtemplate", NewData().Set("first", "first"))
+	report := sender.Send([]*wechatmp.TemplateMessage{valid})
+	if loads != 2 || sent != 1 || report.Succeeded != 1 || report.Results[0].Attempts != 1 {
+		t.Fatal(loads, report.Results[0])
+	}
+	unknown, _ := NewMessage("unknown", "unknowntemplate", NewData().Set("first", "first"))
+	report = sender.Send([]*wechatmp.TemplateMessage{unknown})
+	r := report.Results[0]
+	if _, ok := r.Err.(ValidationErrors); ok || !fetcher.CompareAPIErrCode(r.Err, 40013) || loads != 3 || sent != 1 || r.Attempts != 0 {
+		t.Fatal(loads, r)
+	}
+}
+
+func TestBatchSenderHighRate(t *testing.T) {
+	sent := 0
+	s := NewBatchSenderWithFunc(func(m *wechatmp.TemplateMessage) (*wechatmp.TemplateMessageSendResult, error) {
+		sent++
+		return &wechatmp.TemplateMessageSendResult{MsgID: 1}, nil
+	})
+	s.Concurrency = 1
+	s.RatePerSecond = 2000000000
+	report := s.Send([]*wechatmp.TemplateMessage{wechatmp.NewTemplateMessage(), wechatmp.NewTemplateMessage()})
+	if sent != 2 || report.Succeeded != 2 {
+		t.Fatal(report)
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	s := wechatmptest.NewServer()
+	defer s.Close()
+	s.Replace(t, &wechatmp.APIMessageTemplateSend, "POST", "/cgi-bin/message/template/send")
+	s.Handle("/cgi-bin/message/template/send", func(r *wechatmptest.Request) interface{} {
+		return &wechatmp.ResultAPIError{Errcode: APIErrMinuteQuotaLimit, Errmsg: "api minute-quota reach limit"}
+	})
+	_, err := SendTemplateMessage(s.NewApp(), wechatmp.NewTemplateMessage())
+	if err == nil || !IsTransientError(err) {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	_, err = http.Get("http://" + addr)
+	if err == nil || !IsTransientError(err) {
+		t.Fatal(err)
+	}
+	timeout := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer timeout.Close()
+	_, err = (&http.Client{Timeout: 10 * time.Millisecond}).Get(timeout.URL)
+	if err == nil || IsTransientError(err) {
+		t.Fatal(err)
+	}
+	if IsTransientError(errTestTransient) {
+		t.Fatal(errTestTransient)
+	}
+}
@@ -0,0 +1,49 @@
+package templatemessage
+
+import (
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//DataItem template message data item
+type DataItem struct {
+	Value string `json:"value"`
+	Color string `json:"color,omitempty"`
+}
+
+//Data template message data with keyword as key.
+type Data map[string]*DataItem
+
+//NewData create new template message data.
+func NewData() Data {
+	return Data{}
+}
+
+//Set set value of given keyword.
+func (d Data) Set(keyword string, value string) Data {
+	d[keyword] = &DataItem{
+		Value: value,
+	}
+	return d
+}
+
+//SetWithColor set value and color of given keyword.
+//Color should be like "#173177".
+func (d Data) SetWithColor(keyword string, value string, color string) Data {
+	d[keyword] = &DataItem{
+		Value: value,
+		Color: color,
+	}
+	return d
+}
+
+//NewMessage create new template message with given receiver,template id and data.
+func NewMessage(touser string, templateID string, data Data) (*wechatmp.TemplateMessage, error) {
+	m := wechatmp.NewTemplateMessage()
+	m.ToUser = touser
+	m.TemplateID = templateID
+	err := m.SetData(data)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
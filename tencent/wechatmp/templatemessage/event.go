@@ -0,0 +1,49 @@
+package templatemessage
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+//EventTemplateSendJobFinish event sent when template message send job finished.
+const EventTemplateSendJobFinish = "TEMPLATESENDJOBFINISH"
+
+//StatusSuccess status of template message delivered successfully.
+const StatusSuccess = "success"
+
+//StatusFailedUserBlock status of template message rejected by user.
+const StatusFailedUserBlock = "failed:user block"
+
+//StatusFailedSystemFailed status of template message failed for other reasons.
+const StatusFailedSystemFailed = "failed: system failed"
+
+//TemplateSendJobFinishEvent template message send job finish event
+type TemplateSendJobFinishEvent struct {
+	ToUserName   string
+	FromUserName string
+	CreateTime   int64
+	MsgType      string
+	Event        string
+	MsgID        int64
+	Status       string
+}
+
+//IsSuccess check if template message delivered successfully.
+func (e *TemplateSendJobFinishEvent) IsSuccess() bool {
+	return e.Status == StatusSuccess
+}
+
+//IsUserBlock check if template message rejected by user.
+func (e *TemplateSendJobFinishEvent) IsUserBlock() bool {
+	return strings.HasPrefix(e.Status, "failed") && strings.Contains(e.Status, "user block")
+}
+
+//ParseTemplateSendJobFinishEvent parse template message send job finish event from receiver message content.
+func ParseTemplateSendJobFinishEvent(content []byte) (*TemplateSendJobFinishEvent, error) {
+	e := &TemplateSendJobFinishEvent{}
+	err := xml.Unmarshal(content, e)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,79 @@
+package templatemessage
+
+import (
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//IndustryClass industry class
+type IndustryClass struct {
+	FirstClass  string `json:"first_class"`
+	SecondClass string `json:"second_class"`
+}
+
+//Industry industry of official account
+type Industry struct {
+	PrimaryIndustry   *IndustryClass `json:"primary_industry"`
+	SecondaryIndustry *IndustryClass `json:"secondary_industry"`
+}
+
+type paramsSetIndustry struct {
+	IndustryID1 string `json:"industry_id1"`
+	IndustryID2 string `json:"industry_id2"`
+}
+
+type paramsAddTemplate struct {
+	TemplateIDShort string   `json:"template_id_short"`
+	KeywordNameList []string `json:"keyword_name_list,omitempty"`
+}
+
+type resultAddTemplate struct {
+	TemplateID string `json:"template_id"`
+}
+
+type paramsTemplateID struct {
+	TemplateID string `json:"template_id"`
+}
+
+//SetIndustry set primary and secondary industry by industry ids.
+func SetIndustry(App *wechatmp.App, primary string, secondary string) error {
+	params := &paramsSetIndustry{
+		IndustryID1: primary,
+		IndustryID2: secondary,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APITemplateSetIndustry, nil, params, result)
+}
+
+//GetIndustry get industry of official account.
+func GetIndustry(App *wechatmp.App) (*Industry, error) {
+	result := &Industry{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APITemplateGetIndustry, nil, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//AddTemplate add template from template library by short id and return template id.
+//Keyword names are only required for templates of new library.
+func AddTemplate(App *wechatmp.App, shortID string, keywordNames ...string) (string, error) {
+	params := &paramsAddTemplate{
+		TemplateIDShort: shortID,
+		KeywordNameList: keywordNames,
+	}
+	result := &resultAddTemplate{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APITemplateAdd, nil, params, result)
+	if err != nil {
+		return "", err
+	}
+	return result.TemplateID, nil
+}
+
+//DeletePrivateTemplate delete private template by template id.
+func DeletePrivateTemplate(App *wechatmp.App, templateID string) error {
+	params := &paramsTemplateID{
+		TemplateID: templateID,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APITemplateDelPrivate, nil, params, result)
+}
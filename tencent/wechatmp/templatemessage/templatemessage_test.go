@@ -0,0 +1,63 @@
+package templatemessage
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestData(t *testing.T) {
+	data := NewData().
+		Set("first", "hello").
+		SetWithColor("keyword1", "value1", "#173177")
+	m, err := NewMessage("testopenid", "testtemplateid", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.ToUser != "testopenid" || m.TemplateID != "testtemplateid" {
+		t.Fatal(m)
+	}
+	result := map[string]map[string]string{}
+	err = json.Unmarshal(m.Data, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || result["first"]["value"] != "hello" || len(result["first"]) != 1 {
+		t.Fatal(result)
+	}
+	if result["keyword1"]["value"] != "value1" || result["keyword1"]["color"] != "#173177" {
+		t.Fatal(result)
+	}
+}
+
+func TestParseTemplateSendJobFinishEvent(t *testing.T) {
+	var tests = []struct {
+		status    string
+		success   bool
+		userblock bool
+	}{
+		{"success", true, false},
+		{"failed:user block", false, true},
+		{"failed: system failed", false, false},
+	}
+	for _, test := range tests {
+		content := []byte(`<xml>
+<ToUserName><![CDATA[gh_7f083739789a]]></ToUserName>
+<FromUserName><![CDATA[oia2TjuEGTNoeX76QEjQNrcURxG8]]></FromUserName>
+<CreateTime>1395658920</CreateTime>
+<MsgType><![CDATA[event]]></MsgType>
+<Event><![CDATA[TEMPLATESENDJOBFINISH]]></Event>
+<MsgID>200163836</MsgID>
+<Status><![CDATA[` + test.status + `]]></Status>
+</xml>`)
+		e, err := ParseTemplateSendJobFinishEvent(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Event != EventTemplateSendJobFinish || e.MsgID != 200163836 || e.Status != test.status {
+			t.Fatal(e)
+		}
+		if e.IsSuccess() != test.success || e.IsUserBlock() != test.userblock {
+			t.Fatal(test, e)
+		}
+	}
+}
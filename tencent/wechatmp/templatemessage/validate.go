@@ -0,0 +1,221 @@
+package templatemessage
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//DefaultMaxValueLength default max rune length of data value.
+const DefaultMaxValueLength = 200
+
+//DefaultMinReloadInterval default min interval between template reloads caused by unknown template id.
+const DefaultMinReloadInterval = time.Minute
+
+//Validation error codes
+const (
+	ErrCodeUnknownTemplate = "unknown_template"
+	ErrCodeInvalidData     = "invalid_data"
+	ErrCodeMissingKey      = "missing_key"
+	ErrCodeUnknownKey      = "unknown_key"
+	ErrCodeTooLong         = "too_long"
+)
+
+//DefaultMessages default english messages of validation error codes.
+//Messages are formatted with path as first argument and limit as second argument.
+var DefaultMessages = map[string]string{
+	ErrCodeUnknownTemplate: "%[1]s is not a known template",
+	ErrCodeInvalidData:     "%[1]s is not a valid data object",
+	ErrCodeMissingKey:      "%[1]s is required",
+	ErrCodeUnknownKey:      "%[1]s is not a template key",
+	ErrCodeTooLong:         "%[1]s must not be longer than %[2]d characters",
+}
+
+//ChineseMessages chinese messages of validation error codes.
+var ChineseMessages = map[string]string{
+	ErrCodeUnknownTemplate: "%[1]s 不是已知模板",
+	ErrCodeInvalidData:     "%[1]s 不是有效的数据对象",
+	ErrCodeMissingKey:      "%[1]s 不能为空",
+	ErrCodeUnknownKey:      "%[1]s 不是模板中的关键词",
+	ErrCodeTooLong:         "%[1]s 不能超过%[2]d个字符",
+}
+
+var placeholderRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\.DATA\s*\}\}`)
+
+//ParseKeys parse data keys from template content like "{{first.DATA}}".
+//Keys are returned in order of first appearance.
+func ParseKeys(content string) []string {
+	keys := []string{}
+	found := map[string]bool{}
+	for _, v := range placeholderRegexp.FindAllStringSubmatch(content, -1) {
+		if !found[v[1]] {
+			found[v[1]] = true
+			keys = append(keys, v[1])
+		}
+	}
+	return keys
+}
+
+//ValidationError template message validation error.
+//Path is "template_id","data" or "data.<key>".
+type ValidationError = wechatmp.ValidationError
+
+//ValidationErrors template message validation error list
+type ValidationErrors = wechatmp.ValidationErrors
+
+//NewValidationError create new validation error with DefaultMessages.
+func NewValidationError(path string, code string, limit int) *ValidationError {
+	return wechatmp.NewValidationError(path, code, limit, DefaultMessages)
+}
+
+func dataPath(key string) string {
+	return "data." + key
+}
+
+//Template private template with parsed data keys.
+type Template struct {
+	*wechatmp.PrivateTemplate
+	Keys []string
+}
+
+//NewTemplate create template from private template.
+func NewTemplate(t *wechatmp.PrivateTemplate) *Template {
+	return &Template{
+		PrivateTemplate: t,
+		Keys:            ParseKeys(t.Content),
+	}
+}
+
+//Validate validate message data against template keys.
+//Nil will be returned if message is valid.
+func (t *Template) Validate(m *wechatmp.TemplateMessage, maxValueLength int) ValidationErrors {
+	var errs ValidationErrors
+	data := Data{}
+	if len(m.Data) > 0 {
+		err := json.Unmarshal(m.Data, &data)
+		if err != nil {
+			return append(errs, NewValidationError("data", ErrCodeInvalidData, 0))
+		}
+	}
+	known := map[string]bool{}
+	for _, key := range t.Keys {
+		known[key] = true
+		if data[key] == nil {
+			errs = append(errs, NewValidationError(dataPath(key), ErrCodeMissingKey, 0))
+		}
+	}
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !known[key] {
+			errs = append(errs, NewValidationError(dataPath(key), ErrCodeUnknownKey, 0))
+			continue
+		}
+		if data[key] != nil && maxValueLength > 0 && utf8.RuneCountInString(data[key].Value) > maxValueLength {
+			errs = append(errs, NewValidationError(dataPath(key), ErrCodeTooLong, maxValueLength))
+		}
+	}
+	return errs
+}
+
+//TemplateCache local private template cache used to validate messages.
+type TemplateCache struct {
+	App *wechatmp.App
+	//MaxValueLength max rune length of data value,no limit if not positive.
+	MaxValueLength int
+	//MinReloadInterval min interval between reloads caused by unknown template id.
+	MinReloadInterval time.Duration
+	lock              sync.Mutex
+	templates         map[string]*Template
+	loadedAt          time.Time
+}
+
+//NewTemplateCache create new template cache.
+func NewTemplateCache(app *wechatmp.App) *TemplateCache {
+	return &TemplateCache{
+		App:               app,
+		MaxValueLength:    DefaultMaxValueLength,
+		MinReloadInterval: DefaultMinReloadInterval,
+		templates:         map[string]*Template{},
+	}
+}
+
+//Set replace cached templates with given templates.
+func (c *TemplateCache) Set(templates []wechatmp.PrivateTemplate) {
+	result := make(map[string]*Template, len(templates))
+	for k := range templates {
+		t := templates[k]
+		result[t.TemplateID] = NewTemplate(&t)
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.templates = result
+	c.loadedAt = time.Now()
+}
+
+//Load load all private templates into cache.
+func (c *TemplateCache) Load() error {
+	result, err := GetAllPrivateTemplate(c.App)
+	if err != nil {
+		return err
+	}
+	c.Set(result.TemplateList)
+	return nil
+}
+
+func (c *TemplateCache) get(id string) (*Template, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	t := c.templates[id]
+	return t, t == nil && time.Since(c.loadedAt) >= c.MinReloadInterval
+}
+
+//Get get template by id.
+//Templates will be reloaded if id is not found and MinReloadInterval passed since last load.
+//Nil will be returned if template not found.
+func (c *TemplateCache) Get(id string) (*Template, error) {
+	t, reload := c.get(id)
+	if !reload {
+		return t, nil
+	}
+	err := c.Load()
+	if err != nil {
+		return nil, err
+	}
+	t, _ = c.get(id)
+	return t, nil
+}
+
+//Validate validate message against cached template.
+//ValidationErrors will be returned as error if message is invalid.
+func (c *TemplateCache) Validate(m *wechatmp.TemplateMessage) error {
+	t, err := c.Get(m.TemplateID)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return ValidationErrors{NewValidationError("template_id", ErrCodeUnknownTemplate, 0)}
+	}
+	errs := t.Validate(m, c.MaxValueLength)
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
+
+//Send validate message and send it.
+func (c *TemplateCache) Send(m *wechatmp.TemplateMessage) (*wechatmp.TemplateMessageSendResult, error) {
+	err := c.Validate(m)
+	if err != nil {
+		return nil, err
+	}
+	return SendTemplateMessage(c.App, m)
+}
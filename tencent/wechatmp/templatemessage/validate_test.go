@@ -0,0 +1,76 @@
+package templatemessage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+const testContent = "{{first.DATA}}\n会员卡号：{{keyword1.DATA}}\n有效期：{{ keyword2.DATA }}\n{{remark.DATA}}{{first.DATA}}"
+
+func TestParseKeys(t *testing.T) {
+	keys := ParseKeys(testContent)
+	if strings.Join(keys, ",") != "first,keyword1,keyword2,remark" {
+		t.Fatal(keys)
+	}
+	if keys := ParseKeys("no placeholder"); len(keys) != 0 {
+		t.Fatal(keys)
+	}
+}
+
+func TestTemplateCache(t *testing.T) {
+	c := NewTemplateCache(nil)
+	c.MaxValueLength = 5
+	c.MinReloadInterval = time.Hour
+	c.Set([]wechatmp.PrivateTemplate{
+		{TemplateID: "testtemplate", Content: testContent},
+	})
+	m, err := NewMessage("testopenid", "testtemplate", NewData().
+		Set("first", "first").
+		Set("keyword1", "一二三四五").
+		Set("keyword2", "k2").
+		Set("remark", "r"))
+	if err != nil {
+		panic(err)
+	}
+	err = c.Validate(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err = NewMessage("testopenid", "testtemplate", NewData().
+		Set("first", "first").
+		Set("keyword1", "一二三四五六").
+		Set("keyword3", "k3").
+		Set("remark", "r"))
+	if err != nil {
+		panic(err)
+	}
+	err = c.Validate(m)
+	errs, ok := err.(ValidationErrors)
+	if !ok || len(errs) != 3 {
+		t.Fatal(err)
+	}
+	if errs[0].Code != ErrCodeMissingKey || errs[0].Path != "data.keyword2" ||
+		errs[1].Code != ErrCodeTooLong || errs[1].Path != "data.keyword1" || errs[1].Limit != 5 ||
+		errs[2].Code != ErrCodeUnknownKey || errs[2].Path != "data.keyword3" {
+		t.Fatal(errs)
+	}
+	if errs[1].Error() != "data.keyword1 must not be longer than 5 characters" || errs[1].Localize(ChineseMessages) != "data.keyword1 不能超过5个字符" {
+		t.Fatal(errs[1])
+	}
+	m.TemplateID = "unknown"
+	err = c.Validate(m)
+	errs, ok = err.(ValidationErrors)
+	if !ok || len(errs) != 1 || errs[0].Code != ErrCodeUnknownTemplate || errs[0].Path != "template_id" {
+		t.Fatal(err)
+	}
+	m.TemplateID = "testtemplate"
+	m.Data = []byte("[]")
+	err = c.Validate(m)
+	errs, ok = err.(ValidationErrors)
+	if !ok || len(errs) != 1 || errs[0].Code != ErrCodeInvalidData {
+		t.Fatal(err)
+	}
+}
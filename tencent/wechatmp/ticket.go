@@ -0,0 +1,79 @@
+package wechatmp
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechattoken"
+)
+
+//TicketTypeJSAPI ticket type used by js-sdk config.
+const TicketTypeJSAPI = "jsapi"
+
+//TicketTypeWxCard ticket type used by js-sdk card api.
+const TicketTypeWxCard = "wx_card"
+
+//TicketKey return key by which ticket of given type is saved in store.
+func (a *App) TicketKey(ticketType string) string {
+	return a.AccessTokenKey() + "/ticket/" + ticketType
+}
+
+//GetTicket request new ticket of given type.
+func (a *App) GetTicket(ticketType string) (*wechattoken.Token, error) {
+	params := url.Values{}
+	params.Set("type", ticketType)
+	result := &resultTicket{}
+	err := a.CallJSONApiWithAccessToken(APITicketGet, params, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return wechattoken.NewToken(result.Ticket, result.ExpiresIn), nil
+}
+
+//Ticket return ticket of given type from store.
+//New ticket will be requested and saved if ticket in store is empty or about to expire.
+func (a *App) Ticket(ticketType string) (string, error) {
+	key := a.TicketKey(ticketType)
+	t, err := a.getStore().Get(key)
+	if err != nil {
+		return "", err
+	}
+	a.lock.Lock()
+	ahead := a.getRefreshAhead()
+	a.lock.Unlock()
+	if !t.NeedRefresh(time.Now(), ahead) {
+		return t.Value, nil
+	}
+	a.ticketLock.Lock()
+	defer a.ticketLock.Unlock()
+	t, err = a.getStore().Get(key)
+	if err != nil {
+		return "", err
+	}
+	if !t.NeedRefresh(time.Now(), ahead) {
+		return t.Value, nil
+	}
+	old := ""
+	if t != nil {
+		old = t.Value
+	}
+	t, err = a.GetTicket(ticketType)
+	if err != nil {
+		return "", err
+	}
+	t, err = a.saveToken(key, old, t)
+	if err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
+
+//JSAPITicket return jsapi ticket used by js-sdk config.
+func (a *App) JSAPITicket() (string, error) {
+	return a.Ticket(TicketTypeJSAPI)
+}
+
+//WxCardTicket return wx_card ticket used by js-sdk card api.
+func (a *App) WxCardTicket() (string, error) {
+	return a.Ticket(TicketTypeWxCard)
+}
@@ -0,0 +1,79 @@
+package user
+
+import "github.com/herb-go/providers/tencent/wechatmp"
+
+//Iterator follower iterator which fetches follower list page by page.
+type Iterator struct {
+	App        *wechatmp.App
+	total      int
+	nextOpenID string
+	buffer     []string
+	current    string
+	done       bool
+	err        error
+}
+
+//NewIterator create new follower iterator.
+func NewIterator(App *wechatmp.App) *Iterator {
+	return &Iterator{
+		App: App,
+	}
+}
+
+func (i *Iterator) fetch() {
+	result, err := GetFollowers(i.App, i.nextOpenID)
+	if err != nil {
+		i.err = err
+		i.done = true
+		return
+	}
+	i.total = result.Total
+	i.buffer = result.Data.OpenID
+	//Paging stops when next openid is empty or not moved,otherwise same page would be fetched forever.
+	if len(i.buffer) == 0 || result.NextOpenID == "" || result.NextOpenID == i.nextOpenID {
+		i.done = true
+	}
+	i.nextOpenID = result.NextOpenID
+}
+
+//Next move iterator to next follower.
+//Return false if all followers are iterated or error raised.
+func (i *Iterator) Next() bool {
+	for len(i.buffer) == 0 {
+		if i.done {
+			return false
+		}
+		i.fetch()
+	}
+	i.current = i.buffer[0]
+	i.buffer = i.buffer[1:]
+	return true
+}
+
+//OpenID return openid of current follower.
+func (i *Iterator) OpenID() string {
+	return i.current
+}
+
+//Total return total follower count returned by last page.
+func (i *Iterator) Total() int {
+	return i.total
+}
+
+//Err return error raised while iterating.
+func (i *Iterator) Err() error {
+	return i.err
+}
+
+//Each call given func with every follower openid.
+//Iteration stops when func returns an error.
+func Each(App *wechatmp.App, f func(openid string) error) error {
+	i := NewIterator(App)
+	for i.Next() {
+		err := f(i.OpenID())
+		if err != nil {
+			return err
+		}
+	}
+	return i.Err()
+}
@@ -0,0 +1,126 @@
+package user
+
+import (
+	"net/url"
+
+	"github.com/herb-go/providers/tencent/wechatmp"
+)
+
+//BatchGetLimit max openid count of one batchget request.
+const BatchGetLimit = 100
+
+//UserInfo wechat mp follower info
+type UserInfo struct {
+	Subscribe      int    `json:"subscribe"`
+	OpenID         string `json:"openid"`
+	Language       string `json:"language"`
+	SubscribeTime  int64  `json:"subscribe_time"`
+	UnionID        string `json:"unionid"`
+	Remark         string `json:"remark"`
+	GroupID        int    `json:"groupid"`
+	TagIDList      []int  `json:"tagid_list"`
+	SubscribeScene string `json:"subscribe_scene"`
+	QRScene        int    `json:"qr_scene"`
+	QRSceneStr     string `json:"qr_scene_str"`
+}
+
+//IsSubscribed check if user is subscribing.
+func (u *UserInfo) IsSubscribed() bool {
+	return u.Subscribe == 1
+}
+
+//FollowerListData openid list data of follower list.
+type FollowerListData struct {
+	OpenID []string `json:"openid"`
+}
+
+//FollowerListResult follower list result
+type FollowerListResult struct {
+	Total      int              `json:"total"`
+	Count      int              `json:"count"`
+	Data       FollowerListData `json:"data"`
+	NextOpenID string           `json:"next_openid"`
+}
+
+//GetFollowers get one page of followers after given openid.
+//First page will be returned if nextOpenID is empty.
+func GetFollowers(App *wechatmp.App, nextOpenID string) (*FollowerListResult, error) {
+	params := url.Values{}
+	if nextOpenID != "" {
+		params.Set("next_openid", nextOpenID)
+	}
+	result := &FollowerListResult{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIUserGet, params, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//GetUserInfo get follower info by openid.
+func GetUserInfo(App *wechatmp.App, openid string, lang string) (*UserInfo, error) {
+	params := url.Values{}
+	params.Set("openid", openid)
+	if lang != "" {
+		params.Set("lang", lang)
+	}
+	result := &UserInfo{}
+	err := App.CallJSONApiWithAccessToken(wechatmp.APIUserInfo, params, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type batchGetUser struct {
+	OpenID string `json:"openid"`
+	Lang   string `json:"lang,omitempty"`
+}
+
+type paramsBatchGet struct {
+	UserList []*batchGetUser `json:"user_list"`
+}
+
+type resultBatchGet struct {
+	UserInfoList []*UserInfo `json:"user_info_list"`
+}
+
+//BatchGetUserInfo get followers info by openid list.
+//Openid list will be split into requests with at most BatchGetLimit openids.
+func BatchGetUserInfo(App *wechatmp.App, openids []string, lang string) ([]*UserInfo, error) {
+	list := make([]*UserInfo, 0, len(openids))
+	for start := 0; start < len(openids); start += BatchGetLimit {
+		end := start + BatchGetLimit
+		if end > len(openids) {
+			end = len(openids)
+		}
+		params := &paramsBatchGet{
+			UserList: make([]*batchGetUser, 0, end-start),
+		}
+		for _, v := range openids[start:end] {
+			params.UserList = append(params.UserList, &batchGetUser{OpenID: v, Lang: lang})
+		}
+		result := &resultBatchGet{}
+		err := App.CallJSONApiWithAccessToken(wechatmp.APIUserInfoBatchGet, nil, params, result)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, result.UserInfoList...)
+	}
+	return list, nil
+}
+
+type paramsUpdateRemark struct {
+	OpenID string `json:"openid"`
+	Remark string `json:"remark"`
+}
+
+//UpdateRemark update remark of follower.
+func UpdateRemark(App *wechatmp.App, openid string, remark string) error {
+	params := &paramsUpdateRemark{
+		OpenID: openid,
+		Remark: remark,
+	}
+	result := &wechatmp.ResultAPIError{}
+	return App.CallJSONApiWithAccessToken(wechatmp.APIUserInfoUpdateRemark, nil, params, result)
+}
@@ -0,0 +1,156 @@
+package user
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatmp/internal/wechatmptest"
+)
+
+func page(next string, openids ...string) *FollowerListResult {
+	return &FollowerListResult{
+		Total:      3,
+		Count:      len(openids),
+		Data:       FollowerListData{OpenID: openids},
+		NextOpenID: next,
+	}
+}
+
+func TestIterator(t *testing.T) {
+	var tests = []struct {
+		Name     string
+		Pages    map[string]interface{}
+		Wanted   []string
+		Requests int
+		Err      bool
+	}{
+		{
+			Name: "empty next openid",
+			Pages: map[string]interface{}{
+				"":  page("b", "a", "b"),
+				"b": page("", "c"),
+			},
+			Wanted:   []string{"a", "b", "c"},
+			Requests: 2,
+		},
+		{
+			Name: "empty page",
+			Pages: map[string]interface{}{
+				"":  page("b", "a", "b"),
+				"b": page("c", "c"),
+				"c": page("c"),
+			},
+			Wanted:   []string{"a", "b", "c"},
+			Requests: 3,
+		},
+		{
+			Name: "repeated next openid",
+			Pages: map[string]interface{}{
+				"":  page("b", "a", "b"),
+				"b": page("b", "c"),
+			},
+			Wanted:   []string{"a", "b", "c"},
+			Requests: 2,
+		},
+		{
+			Name: "error",
+			Pages: map[string]interface{}{
+				"":  page("b", "a", "b"),
+				"b": &wechatmp.ResultAPIError{Errcode: 45009, Errmsg: "reach max api daily quota limit"},
+			},
+			Wanted:   []string{"a", "b"},
+			Requests: 2,
+			Err:      true,
+		},
+	}
+	for _, v := range tests {
+		s := wechatmptest.NewServer()
+		s.Replace(t, &wechatmp.APIUserGet, "GET", "/cgi-bin/user/get")
+		pages := v.Pages
+		s.Handle("/cgi-bin/user/get", func(r *wechatmptest.Request) interface{} {
+			return pages[r.Query.Get("next_openid")]
+		})
+		i := NewIterator(s.NewApp())
+		result := []string{}
+		for i.Next() {
+			result = append(result, i.OpenID())
+		}
+		s.Close()
+		if !reflect.DeepEqual(result, v.Wanted) {
+			t.Fatal(v.Name, result)
+		}
+		if len(s.Requests("/cgi-bin/user/get")) != v.Requests {
+			t.Fatal(v.Name, len(s.Requests("/cgi-bin/user/get")))
+		}
+		if (i.Err() != nil) != v.Err {
+			t.Fatal(v.Name, i.Err())
+		}
+		if v.Err && !fetcher.CompareAPIErrCode(i.Err(), 45009) {
+			t.Fatal(v.Name, i.Err())
+		}
+		if !v.Err && i.Total() != 3 {
+			t.Fatal(v.Name, i.Total())
+		}
+	}
+}
+
+func TestBatchGetUserInfo(t *testing.T) {
+	var tests = []struct {
+		Count  int
+		Chunks []int
+	}{
+		{0, []int{}},
+		{1, []int{1}},
+		{100, []int{100}},
+		{101, []int{100, 1}},
+		{250, []int{100, 100, 50}},
+	}
+	for _, v := range tests {
+		s := wechatmptest.NewServer()
+		s.Replace(t, &wechatmp.APIUserInfoBatchGet, "POST", "/cgi-bin/user/info/batchget")
+		s.Handle("/cgi-bin/user/info/batchget", func(r *wechatmptest.Request) interface{} {
+			params := &paramsBatchGet{}
+			err := r.Unmarshal(params)
+			if err != nil {
+				panic(err)
+			}
+			result := &resultBatchGet{}
+			for _, u := range params.UserList {
+				result.UserInfoList = append(result.UserInfoList, &UserInfo{Subscribe: 1, OpenID: u.OpenID, Language: u.Lang})
+			}
+			return result
+		})
+		openids := []string{}
+		for i := 0; i < v.Count; i++ {
+			openids = append(openids, fmt.Sprintf("openid%d", i))
+		}
+		list, err := BatchGetUserInfo(s.NewApp(), openids, "zh_CN")
+		s.Close()
+		if err != nil {
+			t.Fatal(v.Count, err)
+		}
+		chunks := []int{}
+		for _, r := range s.Requests("/cgi-bin/user/info/batchget") {
+			params := &paramsBatchGet{}
+			err = r.Unmarshal(params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			chunks = append(chunks, len(params.UserList))
+		}
+		if !reflect.DeepEqual(chunks, v.Chunks) {
+			t.Fatal(v.Count, chunks)
+		}
+		if len(list) != v.Count {
+			t.Fatal(v.Count, len(list))
+		}
+		for k := range list {
+			if list[k].OpenID != openids[k] || list[k].Language != "zh_CN" || !list[k].IsSubscribed() {
+				t.Fatal(v.Count, list[k])
+			}
+		}
+	}
+}
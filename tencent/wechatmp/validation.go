@@ -0,0 +1,64 @@
+package wechatmp
+
+import (
+	"fmt"
+	"strings"
+)
+
+//ValidationError validation error of field in request params.
+type ValidationError struct {
+	//Path path of failing field,like "button[0].sub_button[1].url".
+	Path string
+	//Code error code
+	Code string
+	//Limit limit of field if error is about a limit.
+	Limit int
+	//Message default message
+	Message string
+}
+
+//Localize format error message with given messages.
+//Messages are formatted with path as first argument and limit as second argument.
+//Default message will be returned if code not found in messages.
+func (e *ValidationError) Localize(messages map[string]string) string {
+	msg, ok := messages[e.Code]
+	if !ok {
+		return e.Message
+	}
+	return fmt.Sprintf(msg, e.Path, e.Limit)
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+//NewValidationError create new validation error with message localized by given default messages.
+func NewValidationError(path string, code string, limit int, defaultMessages map[string]string) *ValidationError {
+	e := &ValidationError{
+		Path:  path,
+		Code:  code,
+		Limit: limit,
+	}
+	e.Message = e.Localize(defaultMessages)
+	return e
+}
+
+//ValidationErrors validation error list
+type ValidationErrors []*ValidationError
+
+//Localize format all error messages with given messages.
+func (e ValidationErrors) Localize(messages map[string]string) []string {
+	result := make([]string, len(e))
+	for k := range e {
+		result[k] = e[k].Localize(messages)
+	}
+	return result
+}
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for k := range e {
+		msgs[k] = e[k].Message
+	}
+	return strings.Join(msgs, "; ")
+}
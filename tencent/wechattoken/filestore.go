@@ -0,0 +1,102 @@
+package wechattoken
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+//FileMode token file mode.
+var FileMode = os.FileMode(0600)
+
+//FileStore file-backed access token store.
+//All tokens are saved in one json file.
+//Tokens in same file should not be written by more than one process at the same time.
+type FileStore struct {
+	//Path token file path
+	Path string
+	lock sync.Mutex
+}
+
+//NewFileStore create new file store with given path.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{
+		Path: path,
+	}
+}
+
+func (s *FileStore) load() (map[string]*Token, error) {
+	tokens := map[string]*Token{}
+	data, err := ioutil.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tokens, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return tokens, nil
+	}
+	err = json.Unmarshal(data, &tokens)
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *FileStore) save(tokens map[string]*Token) error {
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tmp.Name(), FileMode)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+//Get get token by key.
+//Nil will be returned if token does not exist or is expired.
+func (s *FileStore) Get(key string) (*Token, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return validToken(tokens[key]), nil
+}
+
+//CompareAndSet set token by key only if value of current token equals old.
+//Empty old value matches missing or expired token.
+//Return whether token was set.
+func (s *FileStore) CompareAndSet(key string, old string, token *Token) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	if currentValue(tokens[key]) != old {
+		return false, nil
+	}
+	tokens[key] = token
+	return true, s.save(tokens)
+}
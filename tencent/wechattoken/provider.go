@@ -0,0 +1,8 @@
+package wechattoken
+
+//Provider access token provider interface.
+type Provider interface {
+	//RefreshSharedToken return current token if it is different from old,
+	//or refresh and return new token otherwise.
+	RefreshSharedToken(old string) (*Token, error)
+}
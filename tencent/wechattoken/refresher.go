@@ -0,0 +1,82 @@
+package wechattoken
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+//ErrRefresherStarted error raised when starting a started background refresher.
+var ErrRefresherStarted = errors.New("wechattoken: background refresher already started")
+
+//BackgroundRefresher refresher which calls refresh func periodically in background.
+type BackgroundRefresher struct {
+	//Interval refresh interval
+	Interval time.Duration
+	//Refresh refresh func.
+	Refresh func() error
+	//OnError func called when refresh func returns an error.
+	//Errors will be ignored if OnError is nil.
+	OnError func(error)
+	lock    sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+//NewBackgroundRefresher create new background refresher with given interval and refresh func.
+func NewBackgroundRefresher(interval time.Duration, refresh func() error) *BackgroundRefresher {
+	return &BackgroundRefresher{
+		Interval: interval,
+		Refresh:  refresh,
+	}
+}
+
+func (r *BackgroundRefresher) refresh() {
+	err := r.Refresh()
+	if err != nil && r.OnError != nil {
+		r.OnError(err)
+	}
+}
+
+func (r *BackgroundRefresher) run(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	r.refresh()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.refresh()
+		}
+	}
+}
+
+//Start start refreshing in background.
+//Refresh func will be called once immediately.
+func (r *BackgroundRefresher) Start() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop != nil {
+		return ErrRefresherStarted
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run(r.stop, r.done)
+	return nil
+}
+
+//Stop stop refreshing and wait until running refresh finished.
+//Stop a stopped refresher does nothing.
+func (r *BackgroundRefresher) Stop() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+	r.stop = nil
+	r.done = nil
+}
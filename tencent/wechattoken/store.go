@@ -0,0 +1,66 @@
+package wechattoken
+
+import (
+	"sync"
+	"time"
+)
+
+//Store access token store interface.
+type Store interface {
+	//Get get token by key.
+	//Nil should be returned if token does not exist or is expired.
+	Get(key string) (*Token, error)
+	//CompareAndSet set token by key only if value of current token equals old.
+	//Empty old value matches missing or expired token.
+	//Return whether token was set.
+	CompareAndSet(key string, old string, token *Token) (bool, error)
+}
+
+//MemoryStore in-memory access token store.
+type MemoryStore struct {
+	lock   sync.Mutex
+	tokens map[string]*Token
+}
+
+//NewMemoryStore create new in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tokens: map[string]*Token{},
+	}
+}
+
+//Get get token by key.
+//Nil will be returned if token does not exist or is expired.
+func (s *MemoryStore) Get(key string) (*Token, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return validToken(s.tokens[key]), nil
+}
+
+//CompareAndSet set token by key only if value of current token equals old.
+//Empty old value matches missing or expired token.
+//Return whether token was set.
+func (s *MemoryStore) CompareAndSet(key string, old string, token *Token) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if currentValue(s.tokens[key]) != old {
+		return false, nil
+	}
+	s.tokens[key] = token
+	return true, nil
+}
+
+func validToken(t *Token) *Token {
+	if t.IsExpired(time.Now()) {
+		return nil
+	}
+	return t
+}
+
+func currentValue(t *Token) string {
+	t = validToken(t)
+	if t == nil {
+		return ""
+	}
+	return t.Value
+}
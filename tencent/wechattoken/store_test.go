@@ -0,0 +1,75 @@
+package wechattoken
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testStore(t *testing.T, s Store) {
+	token, err := s.Get("key")
+	if token != nil || err != nil {
+		t.Fatal(token, err)
+	}
+	ok, err := s.CompareAndSet("key", "old", NewToken("token1", 7200))
+	if ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	ok, err = s.CompareAndSet("key", "", NewToken("token1", 7200))
+	if !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	token, err = s.Get("key")
+	if token == nil || token.Value != "token1" || token.ExpiresAt.IsZero() || err != nil {
+		t.Fatal(token, err)
+	}
+	ok, err = s.CompareAndSet("key", "", NewToken("token2", 7200))
+	if ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	ok, err = s.CompareAndSet("key", "token1", NewToken("token2", 7200))
+	if !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	token, err = s.Get("key")
+	if token == nil || token.Value != "token2" || err != nil {
+		t.Fatal(token, err)
+	}
+	expired := &Token{Value: "expired", ExpiresAt: time.Now().Add(-time.Second)}
+	ok, err = s.CompareAndSet("key", "token2", expired)
+	if !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	token, err = s.Get("key")
+	if token != nil || err != nil {
+		t.Fatal(token, err)
+	}
+	ok, err = s.CompareAndSet("key", "", NewToken("token3", 0))
+	if !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	token, err = s.Get("key")
+	if token == nil || token.Value != "token3" || err != nil {
+		t.Fatal(token, err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "")
+	if tmpdir == "" || err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	path := filepath.Join(tmpdir, "tokens.json")
+	testStore(t, NewFileStore(path))
+	token, err := NewFileStore(path).Get("key")
+	if token == nil || token.Value != "token3" || err != nil {
+		t.Fatal(token, err)
+	}
+}
@@ -0,0 +1,56 @@
+package wechattoken
+
+import "time"
+
+//DefaultRefreshAhead default duration before expiry when access token should be refreshed.
+var DefaultRefreshAhead = 5 * time.Minute
+
+//Token access token with expiry time.
+type Token struct {
+	//Value token value
+	Value string `json:"value"`
+	//ExpiresAt time when token expires.
+	//Zero value means expiry is unknown.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+//NewToken create new token with given value and expires_in seconds.
+//Expiry will be unknown if expiresIn is not positive.
+func NewToken(value string, expiresIn int) *Token {
+	t := &Token{
+		Value: value,
+	}
+	if expiresIn > 0 {
+		t.ExpiresAt = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	return t
+}
+
+//IsEmpty check if token is nil or has empty value.
+func (t *Token) IsEmpty() bool {
+	return t == nil || t.Value == ""
+}
+
+//IsExpired check if token is expired at given time.
+//Token with unknown expiry never expires.
+func (t *Token) IsExpired(now time.Time) bool {
+	if t.IsEmpty() {
+		return true
+	}
+	if t.ExpiresAt.IsZero() {
+		return false
+	}
+	return !now.Before(t.ExpiresAt)
+}
+
+//NeedRefresh check if token should be refreshed at given time,
+//which means token is empty or will expire in ahead duration.
+func (t *Token) NeedRefresh(now time.Time, ahead time.Duration) bool {
+	if t.IsEmpty() {
+		return true
+	}
+	if t.ExpiresAt.IsZero() {
+		return false
+	}
+	return !now.Add(ahead).Before(t.ExpiresAt)
+}
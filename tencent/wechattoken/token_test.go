@@ -0,0 +1,61 @@
+package wechattoken
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestToken(t *testing.T) {
+	now := time.Now()
+	var empty *Token
+	if !empty.IsEmpty() || !empty.IsExpired(now) || !empty.NeedRefresh(now, time.Minute) {
+		t.Fatal(empty)
+	}
+	unknown := NewToken("token", 0)
+	if unknown.IsEmpty() || unknown.IsExpired(now) || unknown.NeedRefresh(now, time.Minute) {
+		t.Fatal(unknown)
+	}
+	token := NewToken("token", 7200)
+	if token.IsExpired(now) || token.NeedRefresh(now, time.Minute) {
+		t.Fatal(token)
+	}
+	if !token.NeedRefresh(now, 3*time.Hour) {
+		t.Fatal(token)
+	}
+	if !token.IsExpired(now.Add(3 * time.Hour)) {
+		t.Fatal(token)
+	}
+}
+
+func TestBackgroundRefresher(t *testing.T) {
+	var count int32
+	r := NewBackgroundRefresher(time.Millisecond, func() error {
+		atomic.AddInt32(&count, 1)
+		return errors.New("refresh error")
+	})
+	var errcount int32
+	r.OnError = func(err error) {
+		atomic.AddInt32(&errcount, 1)
+	}
+	err := r.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.Start()
+	if err != ErrRefresherStarted {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	r.Stop()
+	r.Stop()
+	c := atomic.LoadInt32(&count)
+	if c < 2 || atomic.LoadInt32(&errcount) != c {
+		t.Fatal(c)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if atomic.LoadInt32(&count) != c {
+		t.Fatal(count)
+	}
+}
@@ -0,0 +1,57 @@
+package tokenserver
+
+import (
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechattoken"
+)
+
+//Client token server client config.
+//Client implements wechattoken.Provider,and could be used in SetAccessTokenProvider of wechat clients.
+//Client embeds fetcher.Server,so config of RemoteRefresher of wechat clients could be reused as it is.
+//To migrate from RemoteRefresher,move RemoteRefresher config into Client,set Secret and Key,
+//pass client to SetAccessTokenProvider and leave RemoteRefresher empty.
+type Client struct {
+	//Server token server url and http client config.
+	fetcher.Server
+	//Secret shared secret of token server.
+	Secret string
+	//Key key of token provider on token server.
+	Key string
+}
+
+//RefreshSharedToken return current token from token server if it is different from old,
+//or let token server refresh and return new token otherwise.
+func (c *Client) RefreshSharedToken(old string) (*wechattoken.Token, error) {
+	req := &RequestToken{
+		Key: c.Key,
+		Old: old,
+	}
+	result := &ResultToken{}
+	preset, err := c.ServerInfo.CreatePreset()
+	if err != nil {
+		return nil, err
+	}
+	preset = preset.With(
+		fetcher.Method("POST"),
+		fetcher.SetHeader("Authorization", AuthorizationPrefix+c.Secret),
+		fetcher.JSONBody(req),
+	)
+	_, err = fetcher.DoAndParse(&c.Client, preset, fetcher.Should200(fetcher.AsJSON(result)))
+	if err != nil {
+		return nil, err
+	}
+	if result.AccessToken == "" {
+		return nil, ErrEmptyToken
+	}
+	return wechattoken.NewToken(result.AccessToken, result.ExpiresIn), nil
+}
+
+//RefreshShared refresh shared data.
+//Client could be used as the same shared refresher as wechat clients.
+func (c *Client) RefreshShared(old []byte) ([]byte, error) {
+	t, err := c.RefreshSharedToken(string(old))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(t.Value), nil
+}
@@ -0,0 +1,28 @@
+package tokenserver
+
+import (
+	"github.com/herb-go/providers/tencent/wechatmp"
+	"github.com/herb-go/providers/tencent/wechatwork"
+)
+
+//Config token server config.
+type Config struct {
+	//Secret shared secret which clients should send.
+	Secret string
+	//Apps wechat mp apps served by key.
+	Apps map[string]*wechatmp.App
+	//Agents wechat work agents served by key.
+	Agents map[string]*wechatwork.Agent
+}
+
+//CreateHandler create handler which serves all configured apps and agents.
+func (c *Config) CreateHandler() *Handler {
+	h := NewHandler(c.Secret)
+	for k, v := range c.Apps {
+		h.Register(k, v)
+	}
+	for k, v := range c.Agents {
+		h.Register(k, v)
+	}
+	return h
+}
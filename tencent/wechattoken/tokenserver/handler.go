@@ -0,0 +1,96 @@
+package tokenserver
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/herb-go/providers/tencent/wechattoken"
+)
+
+//Handler http handler which serves access tokens from registered providers.
+type Handler struct {
+	//Secret shared secret which clients should send in authorization header.
+	//All requests will be rejected if secret is empty.
+	Secret    string
+	lock      sync.RWMutex
+	providers map[string]wechattoken.Provider
+}
+
+//NewHandler create new handler with given secret.
+func NewHandler(secret string) *Handler {
+	return &Handler{
+		Secret:    secret,
+		providers: map[string]wechattoken.Provider{},
+	}
+}
+
+//Register register provider with given key.
+func (h *Handler) Register(key string, p wechattoken.Provider) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.providers[key] = p
+}
+
+//Provider return provider registered with given key.
+//Nil will be returned if provider not found.
+func (h *Handler) Provider(key string) wechattoken.Provider {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return h.providers[key]
+}
+
+//Authorize check if request has valid authorization header.
+func (h *Handler) Authorize(r *http.Request) bool {
+	if h.Secret == "" {
+		return false
+	}
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, AuthorizationPrefix) {
+		return false
+	}
+	secret := strings.TrimPrefix(auth, AuthorizationPrefix)
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(h.Secret)) == 1
+}
+
+//ServeHTTP serve token request.
+//Status 502 will be returned if provider fails.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(405), 405)
+		return
+	}
+	if !h.Authorize(r) {
+		http.Error(w, http.StatusText(401), 401)
+		return
+	}
+	req := &RequestToken{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		http.Error(w, http.StatusText(400), 400)
+		return
+	}
+	p := h.Provider(req.Key)
+	if p == nil {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	t, err := p.RefreshSharedToken(req.Old)
+	if err != nil {
+		http.Error(w, http.StatusText(502), 502)
+		return
+	}
+	if t.IsEmpty() {
+		http.Error(w, http.StatusText(500), 500)
+		return
+	}
+	bs, err := json.Marshal(newResultToken(t))
+	if err != nil {
+		http.Error(w, http.StatusText(500), 500)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs)
+}
@@ -0,0 +1,125 @@
+package tokenserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/herb-go/providers/tencent/wechattoken"
+)
+
+type testProvider struct {
+	token *wechattoken.Token
+	count int
+}
+
+func (p *testProvider) RefreshSharedToken(old string) (*wechattoken.Token, error) {
+	if old == "error" {
+		return nil, errors.New("refresh error")
+	}
+	if old == "empty" {
+		return &wechattoken.Token{}, nil
+	}
+	if p.token.Value == old {
+		p.count++
+		p.token = wechattoken.NewToken(old+"new", 7200)
+	}
+	return p.token, nil
+}
+
+func testRequest(h http.Handler, method string, secret string, body interface{}) *httptest.ResponseRecorder {
+	bs, err := json.Marshal(body)
+	if err != nil {
+		panic(err)
+	}
+	r := httptest.NewRequest(method, "/", bytes.NewBuffer(bs))
+	if secret != "" {
+		r.Header.Set("Authorization", AuthorizationPrefix+secret)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	p := &testProvider{token: wechattoken.NewToken("token", 7200)}
+	h := NewHandler("secret")
+	h.Register("app", p)
+	req := &RequestToken{Key: "app", Old: ""}
+	if w := testRequest(h, "GET", "secret", req); w.Code != 405 {
+		t.Fatal(w.Code)
+	}
+	if w := testRequest(h, "POST", "", req); w.Code != 401 {
+		t.Fatal(w.Code)
+	}
+	if w := testRequest(h, "POST", "wrong", req); w.Code != 401 {
+		t.Fatal(w.Code)
+	}
+	if w := testRequest(h, "POST", "secret", &RequestToken{Key: "notexist"}); w.Code != 404 {
+		t.Fatal(w.Code)
+	}
+	if w := testRequest(h, "POST", "secret", &RequestToken{Key: "app", Old: "error"}); w.Code != 502 {
+		t.Fatal(w.Code)
+	}
+	if w := testRequest(h, "POST", "secret", &RequestToken{Key: "app", Old: "empty"}); w.Code != 500 {
+		t.Fatal(w.Code)
+	}
+	w := testRequest(h, "POST", "secret", req)
+	result := &ResultToken{}
+	err := json.Unmarshal(w.Body.Bytes(), result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != 200 || result.AccessToken != "token" || result.ExpiresIn <= 0 || p.count != 0 {
+		t.Fatal(w.Code, result, p.count)
+	}
+	w = testRequest(h, "POST", "secret", &RequestToken{Key: "app", Old: "token"})
+	err = json.Unmarshal(w.Body.Bytes(), result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != 200 || result.AccessToken != "tokennew" || p.count != 1 {
+		t.Fatal(w.Code, result, p.count)
+	}
+	empty := NewHandler("")
+	empty.Register("app", p)
+	if w := testRequest(empty, "POST", "", req); w.Code != 401 {
+		t.Fatal(w.Code)
+	}
+}
+
+func TestClient(t *testing.T) {
+	p := &testProvider{token: wechattoken.NewToken("token", 7200)}
+	h := NewHandler("secret")
+	h.Register("app", p)
+	s := httptest.NewServer(h)
+	defer s.Close()
+	c := &Client{Secret: "secret", Key: "app"}
+	c.URL = s.URL
+	token, err := c.RefreshSharedToken("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Value != "token" || token.ExpiresAt.IsZero() || p.count != 0 {
+		t.Fatal(token, p.count)
+	}
+	data, err := c.RefreshShared([]byte("token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "tokennew" || p.count != 1 {
+		t.Fatal(string(data), p.count)
+	}
+	_, err = c.RefreshSharedToken("error")
+	if err == nil {
+		t.Fatal(err)
+	}
+	c.Secret = "wrong"
+	_, err = c.RefreshSharedToken("")
+	if err == nil {
+		t.Fatal(err)
+	}
+}
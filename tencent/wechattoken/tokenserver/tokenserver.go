@@ -0,0 +1,43 @@
+package tokenserver
+
+import (
+	"errors"
+	"time"
+
+	"github.com/herb-go/providers/tencent/wechattoken"
+)
+
+//AuthorizationPrefix prefix of authorization header value.
+const AuthorizationPrefix = "Bearer "
+
+//ErrEmptyToken error raised when token server returns empty token.
+var ErrEmptyToken = errors.New("tokenserver: empty token returned")
+
+//RequestToken token request sent to token server.
+type RequestToken struct {
+	//Key key of token provider.
+	Key string `json:"key"`
+	//Old token rejected or held by client.
+	Old string `json:"old"`
+}
+
+//ResultToken token result returned by token server.
+type ResultToken struct {
+	AccessToken string `json:"access_token"`
+	//ExpiresIn seconds before token expires.
+	//Zero means expiry is unknown.
+	ExpiresIn int `json:"expires_in"`
+}
+
+func newResultToken(t *wechattoken.Token) *ResultToken {
+	result := &ResultToken{
+		AccessToken: t.Value,
+	}
+	if !t.ExpiresAt.IsZero() {
+		result.ExpiresIn = int(time.Until(t.ExpiresAt) / time.Second)
+		if result.ExpiresIn <= 0 {
+			result.ExpiresIn = 1
+		}
+	}
+	return result
+}
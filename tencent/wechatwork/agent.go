@@ -5,9 +5,12 @@ import (
 	"io"
 	"mime/multipart"
 	"net/url"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechattoken"
 	"github.com/herb-go/remoteprocedure/fetcherapi/sharedrefresherapi"
 )
 
@@ -17,45 +20,117 @@ type Agent struct {
 	Secret               string
 	RemoteRefresher      *fetcher.Server
 	Client               fetcher.Client
-	accessToken          string
+	store                wechattoken.Store
+	refreshAhead         time.Duration
+	refreshing           bool
 	lock                 sync.Mutex
+	grantLock            sync.Mutex
 	accessTokenRefresher func(string) (string, error)
+	accessTokenProvider  wechattoken.Provider
 	accessTokenGetter    func() (string, error)
 }
 
 //RefreshShared refresh shared data.
 //New data what different from old should be returned
 func (a *Agent) RefreshShared(old []byte) ([]byte, error) {
-	var t string
-	var err error
+	t, err := a.refreshAccessToken(string(old))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(t.Value), nil
+}
+
+//RefreshSharedToken return current token if it is different from old,
+//or refresh and return new token otherwise.
+func (a *Agent) RefreshSharedToken(old string) (*wechattoken.Token, error) {
+	return a.refreshAccessToken(old)
+}
+
+//SetAccessTokenProvider set provider which new access token is granted from.
+func (a *Agent) SetAccessTokenProvider(p wechattoken.Provider) {
+	a.accessTokenProvider = p
+}
+func (a *Agent) loadAccessToken() (*wechattoken.Token, error) {
+	if a.accessTokenGetter != nil {
+		v, err := a.accessTokenGetter()
+		if err != nil {
+			return nil, err
+		}
+		return wechattoken.NewToken(v, 0), nil
+	}
+	t, err := a.getStore().Get(a.AccessTokenKey())
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return &wechattoken.Token{}, nil
+	}
+	return t, nil
+}
+
+//SetAccessTokenStore set store which access token is saved in.
+//Access token will be saved in memory if store is not set.
+func (a *Agent) SetAccessTokenStore(s wechattoken.Store) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.store = s
+}
+
+//AccessTokenKey return key by which access token is saved in store.
+func (a *Agent) AccessTokenKey() string {
+	return "wechatwork/" + a.CorpID + "/" + strconv.Itoa(a.AgentID)
+}
+
+func (a *Agent) getStore() wechattoken.Store {
 	a.lock.Lock()
 	defer a.lock.Unlock()
-	oldtoken := string(old)
-	t, err = a.loadAccessToken()
+	if a.store == nil {
+		a.store = wechattoken.NewMemoryStore()
+	}
+	return a.store
+}
+
+//saveAccessToken save granted token to store if current token in store is still old.
+//Token in store will be returned if it has been replaced by others.
+func (a *Agent) saveAccessToken(old string, token *wechattoken.Token) (*wechattoken.Token, error) {
+	store := a.getStore()
+	ok, err := store.CompareAndSet(a.AccessTokenKey(), old, token)
 	if err != nil {
 		return nil, err
 	}
-	if t != "" && oldtoken != t {
-		return []byte(t), nil
+	if ok {
+		return token, nil
 	}
-	t, err = a.GrantAccessToken()
+	current, err := store.Get(a.AccessTokenKey())
 	if err != nil {
 		return nil, err
 	}
-	return []byte(t), nil
-}
-func (a *Agent) loadAccessToken() (string, error) {
-	if a.accessTokenGetter != nil {
-		return a.accessTokenGetter()
+	if current.IsEmpty() {
+		return token, nil
 	}
-	return a.accessToken, nil
+	return current, nil
 }
 
 func (a *Agent) AccessToken() (string, error) {
-	a.lock.Lock()
-	defer a.lock.Unlock()
-	return a.loadAccessToken()
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
+
+//AccessTokenExpiresAt return expiry time of current access token.
+//Zero time will be returned if expiry is unknown.
+func (a *Agent) AccessTokenExpiresAt() (time.Time, error) {
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.ExpiresAt, nil
 }
+
+//SetAccessTokenGetter set func which loads access token.
+//Deprecated: use SetAccessTokenStore instead.
 func (a *Agent) SetAccessTokenGetter(f func() (string, error)) {
 	a.accessTokenGetter = f
 }
@@ -63,6 +138,23 @@ func (a *Agent) SetAccessTokenGetter(f func() (string, error)) {
 func (a *Agent) SetAccessTokenRefresher(f func(string) (string, error)) {
 	a.accessTokenRefresher = f
 }
+
+//SetAccessTokenRefreshAhead set duration before expiry when access token should be refreshed.
+//wechattoken.DefaultRefreshAhead will be used if d is not positive.
+func (a *Agent) SetAccessTokenRefreshAhead(d time.Duration) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.refreshAhead = d
+}
+
+//getRefreshAhead return refresh ahead duration.
+//Lock should be held by caller.
+func (a *Agent) getRefreshAhead() time.Duration {
+	if a.refreshAhead > 0 {
+		return a.refreshAhead
+	}
+	return wechattoken.DefaultRefreshAhead
+}
 func (a *Agent) NewMessage() *Message {
 	return &Message{
 		AgentID: a.AgentID,
@@ -84,20 +176,34 @@ func (a *Agent) ClientCredentialBuilder() fetcher.Command {
 		return nil
 	})
 }
-func (a *Agent) getRemoteToken() (string, error) {
-	t, err := a.loadAccessToken()
-	if err != nil {
-		return "", err
+
+//getRemoteToken get access token from remote refresher.
+//Current token will be sent as old token if rejected is empty.
+func (a *Agent) getRemoteToken(rejected string) (string, error) {
+	old := rejected
+	if old == "" {
+		t, err := a.loadAccessToken()
+		if err != nil {
+			return "", err
+		}
+		old = t.Value
 	}
-	data, err := sharedrefresherapi.FetcherRefreshShared(a.RemoteRefresher, []byte(t))
+	data, err := sharedrefresherapi.FetcherRefreshShared(a.RemoteRefresher, []byte(old))
 	if err != nil {
 		return "", err
 	}
 	return string(data), nil
 }
-func (a *Agent) GetAccessToken() (string, error) {
+
+//requestAccessToken request access token from remote refresher or token api.
+//Rejected is sent to remote refresher as old token.
+func (a *Agent) requestAccessToken(rejected string) (*wechattoken.Token, error) {
 	if !a.RemoteRefresher.IsEmpty() {
-		return a.getRemoteToken()
+		t, err := a.getRemoteToken(rejected)
+		if err != nil {
+			return nil, err
+		}
+		return wechattoken.NewToken(t, 0), nil
 	}
 	result := &resultAccessToken{}
 	resp, err := fetcher.DoAndParse(
@@ -106,33 +212,144 @@ func (a *Agent) GetAccessToken() (string, error) {
 		fetcher.Should200(fetcher.AsJSON(result)),
 	)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if result.Errcode != 0 || result.Errmsg == "" || result.AccessToken == "" {
-		return "", resp.NewAPICodeErr(result.Errcode)
+		return nil, resp.NewAPICodeErr(result.Errcode)
 	}
-	return result.AccessToken, nil
+	return wechattoken.NewToken(result.AccessToken, result.ExpiresIn), nil
 }
+func (a *Agent) GetAccessToken() (string, error) {
+	t, err := a.requestAccessToken("")
+	if err != nil {
+		return "", err
+	}
+	return t.Value, nil
+}
+
+//GrantAccessToken grant new access token and save it to store.
 func (a *Agent) GrantAccessToken() (string, error) {
-	var token string
-	var err error
-	a.lock.Lock()
-	defer a.lock.Unlock()
-	token, err = a.loadAccessToken()
+	a.grantLock.Lock()
+	defer a.grantLock.Unlock()
+	t, err := a.loadAccessToken()
 	if err != nil {
 		return "", err
 	}
-	if a.accessTokenRefresher == nil {
-		token, err = a.GetAccessToken()
-	} else {
-		token, err = a.accessTokenRefresher(token)
+	t, err = a.grantAccessToken(t.Value)
+	if err != nil {
+		return "", err
 	}
+	return t.Value, nil
+}
 
+//RefreshAccessToken refresh access token which is rejected with given old value.
+//Current token will be returned without granting if old token has already been replaced.
+func (a *Agent) RefreshAccessToken(old string) (string, error) {
+	t, err := a.refreshAccessToken(old)
 	if err != nil {
 		return "", err
 	}
-	a.accessToken = token
-	return token, nil
+	return t.Value, nil
+}
+
+func (a *Agent) refreshAccessToken(old string) (*wechattoken.Token, error) {
+	a.grantLock.Lock()
+	defer a.grantLock.Unlock()
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	if t.Value != old && !t.IsExpired(time.Now()) {
+		return t, nil
+	}
+	return a.grantAccessToken(old)
+}
+
+//grantAccessToken grant new access token.
+//Rejected is token rejected by server,and is passed to refresher and provider as old token.
+//Current token will be used as old token if rejected is empty.
+//Grant lock should be held by caller.
+func (a *Agent) grantAccessToken(rejected string) (*wechattoken.Token, error) {
+	var token *wechattoken.Token
+	current, err := a.loadAccessToken()
+	if err != nil {
+		return nil, err
+	}
+	old := rejected
+	if old == "" {
+		old = current.Value
+	}
+	switch {
+	case a.accessTokenRefresher != nil:
+		var v string
+		v, err = a.accessTokenRefresher(old)
+		token = wechattoken.NewToken(v, 0)
+	case a.accessTokenProvider != nil:
+		token, err = a.accessTokenProvider.RefreshSharedToken(old)
+	default:
+		token, err = a.requestAccessToken(rejected)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return a.saveAccessToken(current.Value, token)
+}
+
+//RefreshAccessTokenIfNeeded grant new access token if current token is empty or about to expire.
+func (a *Agent) RefreshAccessTokenIfNeeded() error {
+	a.grantLock.Lock()
+	defer a.grantLock.Unlock()
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return err
+	}
+	a.lock.Lock()
+	ahead := a.getRefreshAhead()
+	a.lock.Unlock()
+	if !t.NeedRefresh(time.Now(), ahead) {
+		return nil
+	}
+	_, err = a.grantAccessToken("")
+	return err
+}
+
+//NewBackgroundRefresher create background refresher which checks access token in given interval
+//and refreshes it before expired.
+func (a *Agent) NewBackgroundRefresher(interval time.Duration) *wechattoken.BackgroundRefresher {
+	return wechattoken.NewBackgroundRefresher(interval, a.RefreshAccessTokenIfNeeded)
+}
+
+func (a *Agent) refreshInBackground() {
+	a.RefreshAccessTokenIfNeeded()
+	a.lock.Lock()
+	a.refreshing = false
+	a.lock.Unlock()
+}
+
+//validAccessToken return current access token.
+//New token will be granted if current token is empty or expired.
+//Refresh will be started in background if current token is about to expire.
+func (a *Agent) validAccessToken() (string, error) {
+	t, err := a.loadAccessToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	if t.IsExpired(now) {
+		t, err = a.refreshAccessToken(t.Value)
+		if err != nil {
+			return "", err
+		}
+		return t.Value, nil
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if t.NeedRefresh(now, a.getRefreshAhead()) && !a.refreshing {
+		a.refreshing = true
+		go a.refreshInBackground()
+	}
+	return t.Value, nil
 }
 
 func (a *Agent) CallJSONApiWithAccessToken(api *fetcher.Preset, params url.Values, body interface{}, v interface{}) error {
@@ -167,18 +384,11 @@ func (a *Agent) UploadApiWithAccessToken(api *fetcher.Preset, params url.Values,
 func (a *Agent) callApiWithAccessToken(api *fetcher.Preset, APIPresetBuilder func(accesstoken string) (*fetcher.Preset, error), v interface{}) error {
 	var apierr = &resultAPIError{}
 	var err error
-	token, err := a.AccessToken()
+	token, err := a.validAccessToken()
 	if err != nil {
 		return err
 	}
 
-	if token == "" {
-		token, err = a.GrantAccessToken()
-		if err != nil {
-			return err
-		}
-	}
-
 	preset, err := APIPresetBuilder(token)
 	if err != nil {
 		return err
@@ -190,7 +400,11 @@ func (a *Agent) callApiWithAccessToken(api *fetcher.Preset, APIPresetBuilder fun
 	}
 	if !apierr.IsOK() {
 		if apierr.IsAccessTokenError() {
-			token, err = a.GrantAccessToken()
+			token, err = a.RefreshAccessToken(token)
+			if err != nil {
+				return err
+			}
+			preset, err = APIPresetBuilder(token)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,107 @@
+package wechatwork
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/herb-go/fetcher"
+	"github.com/herb-go/providers/tencent/wechattoken"
+)
+
+type testProvider struct {
+	lock sync.Mutex
+	olds []string
+}
+
+func (p *testProvider) RefreshSharedToken(old string) (*wechattoken.Token, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.olds = append(p.olds, old)
+	return wechattoken.NewToken("new", 7200), nil
+}
+
+func newTestAPIServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "new" {
+			w.Write([]byte(`{"errcode":40001,"errmsg":"invalid credential"}`))
+			return
+		}
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok","value":"result"}`))
+	}))
+}
+
+func newTestAgent(token string) *Agent {
+	agent := &Agent{CorpID: "corpid", AgentID: 1, Secret: "secret"}
+	store := wechattoken.NewMemoryStore()
+	store.CompareAndSet(agent.AccessTokenKey(), "", wechattoken.NewToken(token, 7200))
+	agent.SetAccessTokenStore(store)
+	return agent
+}
+
+func TestRejectedTokenPassedToProvider(t *testing.T) {
+	s := newTestAPIServer()
+	defer s.Close()
+	api := fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint("POST", "/api")
+	agent := newTestAgent("old")
+	provider := &testProvider{}
+	agent.SetAccessTokenProvider(provider)
+	result := &struct {
+		Value string `json:"value"`
+	}{}
+	err := agent.CallJSONApiWithAccessToken(api, nil, nil, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Value != "result" {
+		t.Fatal(result)
+	}
+	if len(provider.olds) != 1 || provider.olds[0] != "old" {
+		t.Fatal(provider.olds)
+	}
+	token, err := agent.AccessToken()
+	if err != nil || token != "new" {
+		t.Fatal(token, err)
+	}
+}
+
+func TestConcurrentRejectedTokenGrantedOnce(t *testing.T) {
+	var lock sync.Mutex
+	var grants int
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		grants++
+		lock.Unlock()
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok","access_token":"new","expires_in":7200}`))
+	}))
+	defer tokenServer.Close()
+	getToken := apiGetToken
+	defer func() {
+		apiGetToken = getToken
+	}()
+	apiGetToken = fetcher.MustPreset(&fetcher.ServerInfo{URL: tokenServer.URL}).EndPoint("GET", "/cgi-bin/gettoken")
+	s := newTestAPIServer()
+	defer s.Close()
+	api := fetcher.MustPreset(&fetcher.ServerInfo{URL: s.URL}).EndPoint("POST", "/api")
+	agent := newTestAgent("old")
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- agent.CallJSONApiWithAccessToken(api, nil, nil, &resultAPIError{})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if grants != 1 {
+		t.Fatal(grants)
+	}
+}